@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// CheckFunc 健康检查函数，返回 nil 表示健康
+type CheckFunc func(ctx context.Context) error
+
+// Health 健康状态
+// 存活（liveness）只要进程可以响应即为健康；就绪（readiness）由 SetReady 与注册的检查项共同决定
+type Health struct {
+	ready  atomic.Bool
+	mu     sync.RWMutex
+	checks map[string]CheckFunc
+}
+
+// NewHealth 创建健康状态，初始为未就绪
+func NewHealth() *Health {
+	return &Health{checks: make(map[string]CheckFunc)}
+}
+
+// SetReady 设置就绪状态
+// 由 bootstrap 在服务启动完成后置为 true，在开始关闭时置为 false
+func (h *Health) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// Ready 返回就绪标记
+func (h *Health) Ready() bool {
+	return h.ready.Load()
+}
+
+// AddCheck 注册就绪检查项（如数据库、注册中心连接）
+// 同名检查项会被覆盖
+func (h *Health) AddCheck(name string, check CheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// Check 执行所有检查项，返回失败的检查项及错误信息
+func (h *Health) Check(ctx context.Context) map[string]string {
+	h.mu.RLock()
+	checks := make(map[string]CheckFunc, len(h.checks))
+	for name, check := range h.checks {
+		checks[name] = check
+	}
+	h.mu.RUnlock()
+
+	failed := make(map[string]string)
+	for name, check := range checks {
+		if err := check(ctx); err != nil {
+			failed[name] = err.Error()
+		}
+	}
+	return failed
+}
@@ -0,0 +1,145 @@
+package admin
+
+import (
+	"encoding/json"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// 构建信息，通过 -ldflags 注入，例如：
+// go build -ldflags "-X github.com/addls/go-boot/admin.GitCommit=$(git rev-parse HEAD) -X github.com/addls/go-boot/admin.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	GitCommit = "" // Git 提交（为空时尝试从 Go 构建信息的 vcs.revision 读取）
+	BuildTime = "" // 构建时间（为空时尝试从 Go 构建信息的 vcs.time 读取）
+)
+
+// startTime 进程启动时间，用于计算运行时长
+var startTime = time.Now()
+
+// BuildInfo 构建信息
+type BuildInfo struct {
+	Service   string `json:"service"`
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit,omitempty"`
+	BuildTime string `json:"buildTime,omitempty"`
+	GoVersion string `json:"goVersion"`
+	Module    string `json:"module,omitempty"`
+	GoBoot    string `json:"goBoot,omitempty"` // go-boot 依赖版本
+}
+
+// RuntimeStats 运行时状态
+type RuntimeStats struct {
+	Uptime       string `json:"uptime"`
+	Goroutines   int    `json:"goroutines"`
+	NumCPU       int    `json:"numCPU"`
+	GOMAXPROCS   int    `json:"gomaxprocs"`
+	HeapAlloc    uint64 `json:"heapAlloc"`
+	HeapInuse    uint64 `json:"heapInuse"`
+	HeapObjects  uint64 `json:"heapObjects"`
+	Sys          uint64 `json:"sys"`
+	NumGC        uint32 `json:"numGC"`
+	PauseTotalNs uint64 `json:"pauseTotalNs"`
+}
+
+// readBuildInfo 读取构建信息
+func readBuildInfo(service, version string) BuildInfo {
+	info := BuildInfo{
+		Service:   service,
+		Version:   version,
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.Module = bi.Main.Path
+	for _, dep := range bi.Deps {
+		if dep.Path == "github.com/addls/go-boot" {
+			info.GoBoot = dep.Version
+			break
+		}
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.GitCommit == "" {
+				info.GitCommit = s.Value
+			}
+		case "vcs.time":
+			if info.BuildTime == "" {
+				info.BuildTime = s.Value
+			}
+		}
+	}
+	return info
+}
+
+// readRuntimeStats 读取运行时状态
+func readRuntimeStats() RuntimeStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return RuntimeStats{
+		Uptime:       time.Since(startTime).Round(time.Second).String(),
+		Goroutines:   runtime.NumGoroutine(),
+		NumCPU:       runtime.NumCPU(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		HeapAlloc:    m.HeapAlloc,
+		HeapInuse:    m.HeapInuse,
+		HeapObjects:  m.HeapObjects,
+		Sys:          m.Sys,
+		NumGC:        m.NumGC,
+		PauseTotalNs: m.PauseTotalNs,
+	}
+}
+
+// sensitiveKeys 敏感字段关键字（小写匹配字段名）
+var sensitiveKeys = []string{"password", "secret", "token", "credential", "dsn", "accesskey", "privatekey"}
+
+// redact 将配置转换为通用结构，并把敏感字段替换为 ******
+func redact(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return redactValue(out), nil
+}
+
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if isSensitive(k) {
+				if s, ok := item.(string); ok && s == "" {
+					continue
+				}
+				val[k] = "******"
+				continue
+			}
+			val[k] = redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = redactValue(item)
+		}
+	}
+	return v
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
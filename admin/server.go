@@ -0,0 +1,168 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Server 管理服务器
+// 独立端口监听，不经过任何业务中间件，也不参与服务注册（未实现 transport.Endpointer）
+// 提供 pprof、配置查看、构建信息、运行时状态、健康检查与监控指标等运维接口
+type Server struct {
+	srv     *http.Server
+	mux     *http.ServeMux
+	addr    string
+	service string
+	version string
+	config  interface{}
+	health  *Health
+}
+
+// Option 管理服务器选项
+type Option func(*Server)
+
+// WithService 设置服务名称与版本（用于构建信息）
+func WithService(name, version string) Option {
+	return func(s *Server) {
+		s.service = name
+		s.version = version
+	}
+}
+
+// WithConfig 设置需要展示的配置（敏感字段会自动脱敏）
+func WithConfig(cfg interface{}) Option {
+	return func(s *Server) {
+		s.config = cfg
+	}
+}
+
+// WithHealth 设置健康状态
+func WithHealth(h *Health) Option {
+	return func(s *Server) {
+		s.health = h
+	}
+}
+
+// NewServer 创建管理服务器
+func NewServer(addr string, opts ...Option) *Server {
+	s := &Server{
+		mux:  http.NewServeMux(),
+		addr: addr,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.health == nil {
+		s.health = NewHealth()
+	}
+
+	// pprof
+	s.mux.HandleFunc("/debug/pprof/", pprof.Index)
+	s.mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	s.mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	s.mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	s.mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	// 运维信息
+	s.mux.HandleFunc("/debug/config", s.handleConfig)
+	s.mux.HandleFunc("/debug/buildinfo", s.handleBuildInfo)
+	s.mux.HandleFunc("/debug/runtime", s.handleRuntime)
+
+	// 健康检查与监控指标
+	s.mux.HandleFunc("/healthz", s.handleLiveness)
+	s.mux.HandleFunc("/readyz", s.handleReadiness)
+	s.mux.Handle("/metrics", promhttp.Handler())
+
+	s.srv = &http.Server{Handler: s.mux}
+	return s
+}
+
+// Handle 注册额外的管理接口
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Health 返回健康状态
+func (s *Server) Health() *Health {
+	return s.health
+}
+
+// Start 启动管理服务器
+func (s *Server) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.srv.BaseContext = func(net.Listener) context.Context {
+		return ctx
+	}
+	log.Infof("[Admin] server listening on: %s", lis.Addr().String())
+	if err := s.srv.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Stop 停止管理服务器
+func (s *Server) Stop(ctx context.Context) error {
+	log.Info("[Admin] server stopping")
+	if err := s.srv.Shutdown(ctx); err != nil {
+		if ctx.Err() != nil {
+			return s.srv.Close()
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, err := redact(s.config)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, cfg)
+}
+
+func (s *Server) handleBuildInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, readBuildInfo(s.service, s.version))
+}
+
+func (s *Server) handleRuntime(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, readRuntimeStats())
+}
+
+func (s *Server) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "UP"})
+}
+
+func (s *Server) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	if !s.health.Ready() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "DOWN"})
+		return
+	}
+	if failed := s.health.Check(r.Context()); len(failed) > 0 {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+			"status": "DOWN",
+			"checks": failed,
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "UP"})
+}
+
+// writeJSON 输出 JSON 响应
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
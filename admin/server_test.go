@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// get 请求管理接口，返回状态码与解析后的 JSON 响应体
+func get(t *testing.T, s *Server, path string) (int, map[string]interface{}) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	s.mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	return w.Code, body
+}
+
+func TestConfig(t *testing.T) {
+	type database struct {
+		DSN      string `json:"dsn"`
+		User     string `json:"user"`
+		Password string `json:"password"`
+	}
+	cfg := struct {
+		Name      string            `json:"name"`
+		Database  database          `json:"database"`
+		Registry  []database        `json:"registry"`
+		AuthToken string            `json:"authToken"`
+		Empty     string            `json:"secret"`
+		Metadata  map[string]string `json:"metadata"`
+	}{
+		Name:      "order",
+		Database:  database{DSN: "root:pwd@tcp(127.0.0.1:3306)/order", User: "root", Password: "pwd"},
+		Registry:  []database{{User: "nacos", Password: "nacos"}},
+		AuthToken: "abc",
+		Metadata:  map[string]string{"zone": "z1", "accessKey": "ak"},
+	}
+	s := NewServer("", WithConfig(cfg))
+
+	code, body := get(t, s, "/debug/config")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	want := map[string]interface{}{
+		"name":      "order",
+		"database":  map[string]interface{}{"dsn": "******", "user": "root", "password": "******"},
+		"registry":  []interface{}{map[string]interface{}{"dsn": "", "user": "nacos", "password": "******"}},
+		"authToken": "******",
+		"secret":    "",
+		"metadata":  map[string]interface{}{"zone": "z1", "accessKey": "******"},
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Fatalf("config = %v, want %v", body, want)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	var dbErr error
+	health := NewHealth()
+	health.AddCheck("db", func(context.Context) error { return dbErr })
+	s := NewServer("", WithHealth(health))
+
+	tests := []struct {
+		name       string
+		ready      bool
+		dbErr      error
+		path       string
+		wantStatus int
+		want       map[string]interface{}
+	}{
+		{name: "liveness before ready", path: "/healthz", wantStatus: http.StatusOK, want: map[string]interface{}{"status": "UP"}},
+		{name: "not ready", path: "/readyz", wantStatus: http.StatusServiceUnavailable, want: map[string]interface{}{"status": "DOWN"}},
+		{name: "ready", ready: true, path: "/readyz", wantStatus: http.StatusOK, want: map[string]interface{}{"status": "UP"}},
+		{
+			name:       "check failed",
+			ready:      true,
+			dbErr:      errors.New("connection refused"),
+			path:       "/readyz",
+			wantStatus: http.StatusServiceUnavailable,
+			want:       map[string]interface{}{"status": "DOWN", "checks": map[string]interface{}{"db": "connection refused"}},
+		},
+		{name: "liveness on check failure", ready: true, dbErr: errors.New("connection refused"), path: "/healthz", wantStatus: http.StatusOK, want: map[string]interface{}{"status": "UP"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health.SetReady(tt.ready)
+			dbErr = tt.dbErr
+			code, body := get(t, s, tt.path)
+			if code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", code, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(body, tt.want) {
+				t.Fatalf("body = %v, want %v", body, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/addls/go-boot/common"
+	"github.com/addls/go-boot/config"
+	"github.com/addls/go-boot/middleware"
+	"github.com/addls/go-boot/response"
+	"github.com/addls/go-boot/selector"
+	kratosLog "github.com/go-kratos/kratos/v2/log"
+	kratosMiddleware "github.com/go-kratos/kratos/v2/middleware"
+	kratosRegistry "github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/transport/grpc"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	ggrpc "google.golang.org/grpc"
+)
+
+// discoveryScheme 通过服务发现调用目标服务的地址前缀
+const discoveryScheme = "discovery:///"
+
+// clientFactoryKey 客户端工厂在 context 中的键
+type clientFactoryKey struct{}
+
+// NewClientContext 返回携带客户端工厂的 context，供 NewGRPCClient / NewHTTPClient 使用
+// bootstrap 将其设置为 Kratos App 的 context，请求处理与生命周期钩子中的 ctx 均携带所属应用的客户端工厂
+func NewClientContext(ctx context.Context, f *ClientFactory) context.Context {
+	return context.WithValue(ctx, clientFactoryKey{}, f)
+}
+
+// ClientFactoryFromContext 返回 context 中的客户端工厂
+func ClientFactoryFromContext(ctx context.Context) (*ClientFactory, bool) {
+	f, ok := ctx.Value(clientFactoryKey{}).(*ClientFactory)
+	return f, ok && f != nil
+}
+
+// ClientFactory 客户端工厂
+// 基于配置的 Discovery 创建调用其他 go-boot 服务的客户端，按目标服务缓存连接，应用停止时统一关闭
+// 每个工厂只关闭自己创建的客户端，同一进程中的多个应用互不影响
+type ClientFactory struct {
+	cfg         *config.Config
+	discovery   kratosRegistry.Discovery
+	middlewares []kratosMiddleware.Middleware
+
+	mu          sync.Mutex
+	grpcConns   map[string]*ggrpc.ClientConn
+	httpClients map[string]*http.Client
+	transports  map[string]*selector.Transport
+}
+
+// NewClientFactory 创建客户端工厂 Provider
+func NewClientFactory(cfg *config.Config, logger kratosLog.Logger, discovery kratosRegistry.Discovery, opts *options) *ClientFactory {
+	// 客户端中间件与服务端默认中间件一一对应
+	middlewares := []kratosMiddleware.Middleware{
+		middleware.ClientMetadata(),
+	}
+	if cfg.Middleware.EnableTracing {
+		middlewares = append(middlewares, middleware.ClientTracing())
+	}
+	middlewares = append(middlewares, middleware.ClientLogging(logger))
+	if cfg.Middleware.EnableMetrics {
+		middlewares = append(middlewares, middleware.ClientMetrics())
+	}
+	middlewares = append(middlewares, opts.clientMiddleware...)
+
+	return &ClientFactory{
+		cfg:         cfg,
+		discovery:   discovery,
+		middlewares: middlewares,
+		grpcConns:   make(map[string]*ggrpc.ClientConn),
+		httpClients: make(map[string]*http.Client),
+		transports:  make(map[string]*selector.Transport),
+	}
+}
+
+// GRPCConn 获取目标服务的 gRPC 连接
+// 同一目标服务只会创建一次连接，额外选项仅在首次创建时生效
+func (f *ClientFactory) GRPCConn(ctx context.Context, target string, opts ...grpc.ClientOption) (*ggrpc.ClientConn, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if conn, ok := f.grpcConns[target]; ok {
+		return conn, nil
+	}
+
+	endpoint, err := f.endpoint(target)
+	if err != nil {
+		return nil, err
+	}
+	// 按 client.balancer 与 client.targets 为目标服务选择负载均衡算法
+	// Kratos 的 grpc.WithOptions 会替换而不是追加，opts 中传入 grpc.WithOptions 时需要同时传入该 DialOption
+	clientOpts := []grpc.ClientOption{
+		grpc.WithEndpoint(endpoint),
+		grpc.WithMiddleware(f.middlewares...),
+		grpc.WithOptions(selector.NewBuilder(&f.cfg.Client, target).DialOption()),
+	}
+	if f.discovery != nil {
+		clientOpts = append(clientOpts, grpc.WithDiscovery(f.discovery))
+		if filters := selector.Filters(f.cfg, target); len(filters) > 0 {
+			clientOpts = append(clientOpts, grpc.WithNodeFilter(filters...))
+		}
+	}
+	if timeout := f.timeout(target); timeout > 0 {
+		clientOpts = append(clientOpts, grpc.WithTimeout(timeout))
+	}
+	clientOpts = append(clientOpts, opts...)
+
+	conn, err := grpc.DialInsecure(ctx, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("dial grpc target %s failed: %w", target, err)
+	}
+	f.grpcConns[target] = conn
+	return conn, nil
+}
+
+// HTTPClient 获取目标服务的 HTTP 客户端
+// 同一目标服务只会创建一次客户端，额外选项仅在首次创建时生效
+func (f *ClientFactory) HTTPClient(ctx context.Context, target string, opts ...http.ClientOption) (*http.Client, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if client, ok := f.httpClients[target]; ok {
+		return client, nil
+	}
+
+	endpoint, err := f.endpoint(target)
+	if err != nil {
+		return nil, err
+	}
+	clientOpts := []http.ClientOption{
+		http.WithMiddleware(f.middlewares...),
+	}
+	// 目标服务开启 envelope 时解码 go-boot 统一响应结构，可通过 opts 覆盖
+	if f.cfg.Client.Targets[target].Envelope {
+		clientOpts = append(clientOpts, response.ClientOptions()...)
+	}
+	var tr *selector.Transport
+	if strings.HasPrefix(endpoint, discoveryScheme) {
+		// Kratos 的 HTTP 客户端只支持全局 Selector，由 selector.Transport 按目标服务选择实例
+		// 通过 opts 替换 Transport 后不再经过服务发现
+		tr, err = selector.NewTransport(ctx, f.discovery, target, selector.NewBuilder(&f.cfg.Client, target), selector.Filters(f.cfg, target)...)
+		if err != nil {
+			return nil, fmt.Errorf("watch http target %s failed: %w", target, err)
+		}
+		clientOpts = append(clientOpts, http.WithEndpoint(target), http.WithTransport(tr))
+	} else {
+		clientOpts = append(clientOpts, http.WithEndpoint(endpoint))
+	}
+	if timeout := f.timeout(target); timeout > 0 {
+		clientOpts = append(clientOpts, http.WithTimeout(timeout))
+	}
+	clientOpts = append(clientOpts, opts...)
+
+	client, err := http.NewClient(ctx, clientOpts...)
+	if err != nil {
+		if tr != nil {
+			_ = tr.Close()
+		}
+		return nil, fmt.Errorf("create http client for target %s failed: %w", target, err)
+	}
+	f.httpClients[target] = client
+	if tr != nil {
+		f.transports[target] = tr
+	}
+	return client, nil
+}
+
+// Close 关闭所有已创建的客户端
+func (f *ClientFactory) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var errs []error
+	for target, conn := range f.grpcConns {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close grpc target %s failed: %w", target, err))
+		}
+		delete(f.grpcConns, target)
+	}
+	for target, client := range f.httpClients {
+		if err := client.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close http target %s failed: %w", target, err))
+		}
+		delete(f.httpClients, target)
+	}
+	for target, tr := range f.transports {
+		if err := tr.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("stop watching http target %s failed: %w", target, err))
+		}
+		delete(f.transports, target)
+	}
+	return errors.Join(errs...)
+}
+
+// endpoint 计算目标服务地址
+// 优先使用 client.targets 中配置的直连地址，否则通过服务发现（discovery:///<target>）
+func (f *ClientFactory) endpoint(target string) (string, error) {
+	if t, ok := f.cfg.Client.Targets[target]; ok && t.Endpoint != "" {
+		return t.Endpoint, nil
+	}
+	if f.discovery == nil {
+		return "", fmt.Errorf("discovery is not configured and no endpoint set for target %s", target)
+	}
+	return discoveryScheme + target, nil
+}
+
+// timeout 计算目标服务超时，未配置时返回 0（使用 Kratos 默认值）
+func (f *ClientFactory) timeout(target string) time.Duration {
+	if t, ok := f.cfg.Client.Targets[target]; ok {
+		if timeout := common.ParseTimeout(t.Timeout); timeout > 0 {
+			return timeout
+		}
+	}
+	return common.ParseTimeout(f.cfg.Client.Timeout)
+}
+
+// errNoClientFactory ctx 中没有客户端工厂
+var errNoClientFactory = errors.New("client factory not found in context, use the context of a request or lifecycle hook, or inject *bootstrap.ClientFactory")
+
+// NewGRPCClient 使用 ctx 所属应用的客户端工厂获取目标服务的 gRPC 连接
+// 例如：conn, err := bootstrap.NewGRPCClient(ctx, "service-order")
+// 连接由底座缓存，并在应用停止时统一关闭，业务代码无需手动 Close
+func NewGRPCClient(ctx context.Context, target string, opts ...grpc.ClientOption) (*ggrpc.ClientConn, error) {
+	f, ok := ClientFactoryFromContext(ctx)
+	if !ok {
+		return nil, errNoClientFactory
+	}
+	return f.GRPCConn(ctx, target, opts...)
+}
+
+// NewHTTPClient 使用 ctx 所属应用的客户端工厂获取目标服务的 HTTP 客户端
+// 例如：client, err := bootstrap.NewHTTPClient(ctx, "service-order")
+// 客户端由底座缓存，并在应用停止时统一关闭，业务代码无需手动 Close
+func NewHTTPClient(ctx context.Context, target string, opts ...http.ClientOption) (*http.Client, error) {
+	f, ok := ClientFactoryFromContext(ctx)
+	if !ok {
+		return nil, errNoClientFactory
+	}
+	return f.HTTPClient(ctx, target, opts...)
+}
@@ -0,0 +1,146 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/addls/go-boot/admin"
+	"github.com/addls/go-boot/config"
+	"github.com/addls/go-boot/middleware"
+	"github.com/addls/go-boot/registry/static"
+	"github.com/addls/go-boot/response"
+	kratosLog "github.com/go-kratos/kratos/v2/log"
+	kratosRegistry "github.com/go-kratos/kratos/v2/registry"
+	"google.golang.org/grpc/connectivity"
+)
+
+// newTestFactory 创建使用 targets 配置与 discovery 的客户端工厂
+func newTestFactory(t *testing.T, targets map[string]config.ClientTarget, discovery kratosRegistry.Discovery) *ClientFactory {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Client.Targets = targets
+	f := NewClientFactory(cfg, kratosLog.DefaultLogger, discovery, &options{})
+	t.Cleanup(func() { _ = f.Close() })
+	return f
+}
+
+func TestClientFactoryPerApp(t *testing.T) {
+	targets := map[string]config.ClientTarget{"order": {Endpoint: "127.0.0.1:9000"}}
+	first, second := newTestFactory(t, targets, nil), newTestFactory(t, targets, nil)
+	ctx := context.Background()
+
+	if _, err := NewGRPCClient(ctx, "order"); err == nil {
+		t.Fatal("NewGRPCClient() without a client factory in ctx should fail")
+	}
+	if _, err := NewHTTPClient(ctx, "order"); err == nil {
+		t.Fatal("NewHTTPClient() without a client factory in ctx should fail")
+	}
+
+	firstConn, err := NewGRPCClient(NewClientContext(ctx, first), "order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := first.GRPCConn(ctx, "order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached != firstConn {
+		t.Fatal("the same target should reuse the cached connection")
+	}
+	secondConn, err := NewGRPCClient(NewClientContext(ctx, second), "order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secondConn == firstConn {
+		t.Fatal("factories should not share connections")
+	}
+
+	// 关闭一个工厂不影响另一个工厂的连接
+	if err := second.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if state := secondConn.GetState(); state != connectivity.Shutdown {
+		t.Fatalf("closed factory connection state = %s, want Shutdown", state)
+	}
+	if state := firstConn.GetState(); state == connectivity.Shutdown {
+		t.Fatal("closing another factory should not close this connection")
+	}
+
+	if _, err := newTestFactory(t, nil, nil).GRPCConn(ctx, "order"); err == nil {
+		t.Fatal("GRPCConn() without discovery or endpoint should fail")
+	}
+}
+
+func TestClientFactoryHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response.Success(map[string]string{"name": "order-1"}))
+	}))
+	defer srv.Close()
+	discovery := static.New(map[string][]string{"order": {srv.URL}})
+
+	type order struct {
+		Name string `json:"name"`
+	}
+	tests := []struct {
+		name      string
+		target    config.ClientTarget
+		discovery kratosRegistry.Discovery
+		want      string
+	}{
+		{name: "envelope disabled by default", target: config.ClientTarget{Endpoint: srv.URL}, want: ""},
+		{name: "envelope enabled", target: config.ClientTarget{Endpoint: srv.URL, Envelope: true}, want: "order-1"},
+		{name: "discovery", target: config.ClientTarget{Envelope: true}, discovery: discovery, want: "order-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestFactory(t, map[string]config.ClientTarget{"order": tt.target}, tt.discovery)
+			client, err := NewHTTPClient(NewClientContext(context.Background(), f), "order")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var reply order
+			if err := client.Invoke(context.Background(), http.MethodGet, "/orders/1", nil, &reply); err != nil {
+				t.Fatal(err)
+			}
+			if reply.Name != tt.want {
+				t.Fatalf("reply.Name = %q, want %q", reply.Name, tt.want)
+			}
+			if err := f.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if len(f.httpClients) != 0 || len(f.transports) != 0 {
+				t.Fatal("Close() should release http clients and discovery watchers")
+			}
+		})
+	}
+}
+
+func TestKratosAppClientContext(t *testing.T) {
+	errAbort := errors.New("abort")
+	var got *ClientFactory
+	opts := NewOptions(WithHooks(Hook{Name: "client", OnStart: func(ctx context.Context) error {
+		got, _ = ClientFactoryFromContext(ctx)
+		return errAbort
+	}}))
+	cfg := &config.Config{}
+	logger := kratosLog.DefaultLogger
+	clients := NewClientFactory(cfg, logger, nil, opts)
+
+	// 生命周期钩子的 ctx 携带本应用的客户端工厂
+	app, cleanup, err := NewKratosApp("test", cfg, logger, nil, nil, nil, admin.NewHealth(), NewLifecycle(logger, opts), clients, middleware.NewInFlightTracker(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	if err := app.Run(); !errors.Is(err, errAbort) {
+		t.Fatalf("Run() error = %v, want %v", err, errAbort)
+	}
+	if got != clients {
+		t.Fatal("lifecycle hook ctx should carry the app's client factory")
+	}
+}
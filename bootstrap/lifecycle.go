@@ -0,0 +1,144 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	kratosLog "github.com/go-kratos/kratos/v2/log"
+)
+
+const (
+	defaultHookTimeout = 30 * time.Second // 单个钩子默认超时
+	slowHookThreshold  = time.Second      // 慢钩子告警阈值
+)
+
+// Hook 生命周期钩子
+// 用于数据库连接池、消息消费者、缓存等业务组件的启动与关闭
+type Hook struct {
+	Name    string                          // 钩子名称（用于日志与错误信息）
+	OnStart func(ctx context.Context) error // 启动函数（可选）
+	OnStop  func(ctx context.Context) error // 关闭函数（可选）
+	Timeout time.Duration                   // 单个钩子超时（可选，默认 30s）
+}
+
+// Lifecycle 生命周期管理
+// 按注册顺序启动，按启动的逆序关闭（后启动的组件先关闭）
+// 启动在服务器启动前执行，关闭在服务器停止后执行
+type Lifecycle struct {
+	logger *kratosLog.Helper
+
+	mu      sync.Mutex
+	hooks   []Hook
+	started []Hook
+}
+
+// NewLifecycle 创建生命周期管理 Provider
+func NewLifecycle(logger kratosLog.Logger, opts *options) *Lifecycle {
+	l := &Lifecycle{
+		logger: kratosLog.NewHelper(logger),
+	}
+	for _, hook := range opts.hooks {
+		l.Append(hook)
+	}
+	return l
+}
+
+// Append 注册生命周期钩子
+// 需要在应用启动前注册
+func (l *Lifecycle) Append(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook)
+}
+
+// OnStart 注册启动函数
+func (l *Lifecycle) OnStart(name string, fn func(ctx context.Context) error) {
+	l.Append(Hook{Name: name, OnStart: fn})
+}
+
+// OnStop 注册关闭函数
+func (l *Lifecycle) OnStop(name string, fn func(ctx context.Context) error) {
+	l.Append(Hook{Name: name, OnStop: fn})
+}
+
+// Start 按注册顺序执行启动函数
+// 任一钩子失败时，逆序关闭已启动的钩子并返回错误
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, hook := range l.hooks {
+		if hook.OnStart != nil {
+			if err := l.run(ctx, "start", hook, hook.OnStart); err != nil {
+				if stopErr := l.stop(ctx); stopErr != nil {
+					return errors.Join(err, stopErr)
+				}
+				return err
+			}
+		}
+		l.started = append(l.started, hook)
+	}
+	return nil
+}
+
+// Stop 按启动的逆序执行关闭函数，汇总所有错误
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stop(ctx)
+}
+
+func (l *Lifecycle) stop(ctx context.Context) error {
+	// 关闭时应用上下文通常已取消，这里只保留其中的值
+	ctx = context.WithoutCancel(ctx)
+
+	var errs []error
+	for i := len(l.started) - 1; i >= 0; i-- {
+		hook := l.started[i]
+		if hook.OnStop == nil {
+			continue
+		}
+		if err := l.run(ctx, "stop", hook, hook.OnStop); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	l.started = nil
+	return errors.Join(errs...)
+}
+
+// run 在超时控制下执行钩子，记录失败与慢钩子
+func (l *Lifecycle) run(ctx context.Context, phase string, hook Hook, fn func(ctx context.Context) error) error {
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startTime := time.Now()
+	err := fn(ctx)
+	latency := time.Since(startTime)
+
+	if err != nil {
+		l.logger.Log(kratosLog.LevelError,
+			"msg", "lifecycle hook failed",
+			"hook", hook.Name,
+			"phase", phase,
+			"latency", latency.Seconds(),
+			"error", err,
+		)
+		return fmt.Errorf("lifecycle hook %s %s failed: %w", hook.Name, phase, err)
+	}
+	if latency > slowHookThreshold {
+		l.logger.Log(kratosLog.LevelWarn,
+			"msg", "lifecycle hook slow",
+			"hook", hook.Name,
+			"phase", phase,
+			"latency", latency.Seconds(),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	kratosLog "github.com/go-kratos/kratos/v2/log"
+)
+
+// recorder 记录钩子的执行顺序
+type recorder struct {
+	calls []string
+}
+
+func (r *recorder) hook(name string, startErr, stopErr error) Hook {
+	return Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			r.calls = append(r.calls, "start "+name)
+			return startErr
+		},
+		OnStop: func(context.Context) error {
+			r.calls = append(r.calls, "stop "+name)
+			return stopErr
+		},
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	errStart, errStop := errors.New("start failed"), errors.New("stop failed")
+
+	tests := []struct {
+		name         string
+		hooks        func(r *recorder) []Hook
+		wantStartErr error
+		wantStopErr  error
+		want         []string
+	}{
+		{
+			name: "reverse order stop",
+			hooks: func(r *recorder) []Hook {
+				return []Hook{r.hook("db", nil, nil), r.hook("cache", nil, nil), r.hook("consumer", nil, nil)}
+			},
+			want: []string{"start db", "start cache", "start consumer", "stop consumer", "stop cache", "stop db"},
+		},
+		{
+			name: "start failure stops started hooks",
+			hooks: func(r *recorder) []Hook {
+				return []Hook{r.hook("db", nil, nil), r.hook("cache", errStart, nil), r.hook("consumer", nil, nil)}
+			},
+			wantStartErr: errStart,
+			want:         []string{"start db", "start cache", "stop db"},
+		},
+		{
+			name: "stop errors are joined and all hooks stop",
+			hooks: func(r *recorder) []Hook {
+				return []Hook{r.hook("db", nil, errStop), r.hook("cache", nil, nil)}
+			},
+			wantStopErr: errStop,
+			want:        []string{"start db", "start cache", "stop cache", "stop db"},
+		},
+		{
+			name: "optional functions",
+			hooks: func(r *recorder) []Hook {
+				return []Hook{
+					{Name: "start only", OnStart: func(context.Context) error { r.calls = append(r.calls, "start only"); return nil }},
+					{Name: "stop only", OnStop: func(context.Context) error { r.calls = append(r.calls, "stop only"); return nil }},
+				}
+			},
+			want: []string{"start only", "stop only"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			lc := NewLifecycle(kratosLog.DefaultLogger, NewOptions(WithHooks(tt.hooks(r)...)))
+			ctx := context.Background()
+
+			err := lc.Start(ctx)
+			if !errors.Is(err, tt.wantStartErr) || (err == nil) != (tt.wantStartErr == nil) {
+				t.Fatalf("Start() error = %v, want %v", err, tt.wantStartErr)
+			}
+			if err == nil {
+				err = lc.Stop(ctx)
+				if !errors.Is(err, tt.wantStopErr) || (err == nil) != (tt.wantStopErr == nil) {
+					t.Fatalf("Stop() error = %v, want %v", err, tt.wantStopErr)
+				}
+			}
+			if !reflect.DeepEqual(r.calls, tt.want) {
+				t.Fatalf("calls = %v, want %v", r.calls, tt.want)
+			}
+
+			// 重复关闭不会再次执行钩子
+			if err := lc.Stop(ctx); err != nil {
+				t.Fatalf("second Stop() error = %v", err)
+			}
+			if !reflect.DeepEqual(r.calls, tt.want) {
+				t.Fatalf("calls after second Stop = %v, want %v", r.calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestLifecycleHookTimeout(t *testing.T) {
+	lc := NewLifecycle(kratosLog.DefaultLogger, NewOptions())
+	lc.Append(Hook{
+		Name:    "slow",
+		Timeout: 10 * time.Millisecond,
+		OnStart: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+	if err := lc.Start(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Start() error = %v, want DeadlineExceeded", err)
+	}
+
+	// 关闭时即使应用上下文已取消，钩子仍然获得未取消的上下文
+	var stopErr error
+	lc = NewLifecycle(kratosLog.DefaultLogger, NewOptions())
+	lc.OnStop("db", func(ctx context.Context) error {
+		stopErr = ctx.Err()
+		return nil
+	})
+	if err := lc.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := lc.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if stopErr != nil {
+		t.Fatalf("stop hook context error = %v, want nil", stopErr)
+	}
+}
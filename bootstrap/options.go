@@ -19,7 +19,9 @@ type options struct {
 	httpRegisters    []func(*http.Server) // HTTP 路由注册函数（在服务器创建后调用）
 	grpcRegisters    []func(*grpc.Server) // gRPC 服务注册函数（在服务器创建后调用）
 	customMiddleware []middleware.Middleware
+	clientMiddleware []middleware.Middleware
 	appOpts          []kratos.Option
+	hooks            []Hook
 }
 
 // WithConfigFile 指定配置文件路径
@@ -74,9 +76,26 @@ func WithMiddleware(middlewares ...middleware.Middleware) Option {
 	}
 }
 
+// WithClientMiddleware 添加自定义客户端中间件
+// 作用于 NewGRPCClient / NewHTTPClient 创建的客户端，在默认客户端中间件之后执行
+func WithClientMiddleware(middlewares ...middleware.Middleware) Option {
+	return func(o *options) {
+		o.clientMiddleware = append(o.clientMiddleware, middlewares...)
+	}
+}
+
+// WithHooks 注册生命周期钩子
+// 按注册顺序在服务器启动前执行 OnStart，在服务器停止后按逆序执行 OnStop
+func WithHooks(hooks ...Hook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hooks...)
+	}
+}
+
 // WithAppOptions 添加额外的 Kratos App 选项
 // 用于配置生命周期钩子等业务特定选项
 // 注意：服务注册通过配置文件自动处理，无需手动配置
+// 传入 kratos.Context 时使用 NewClientContext 包装，否则 NewGRPCClient / NewHTTPClient 无法从 ctx 获取客户端工厂
 func WithAppOptions(opts ...kratos.Option) Option {
 	return func(o *options) {
 		o.appOpts = append(o.appOpts, opts...)
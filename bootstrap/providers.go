@@ -1,15 +1,20 @@
 package bootstrap
 
 import (
-	"os"
+	"context"
+	"fmt"
+	"syscall"
 
+	"github.com/addls/go-boot/admin"
 	"github.com/addls/go-boot/common"
 	"github.com/addls/go-boot/config"
+	"github.com/addls/go-boot/i18n"
 	"github.com/addls/go-boot/log"
 	"github.com/addls/go-boot/middleware"
 	"github.com/addls/go-boot/registry"
 	"github.com/addls/go-boot/response"
 	"github.com/go-kratos/kratos/v2"
+	"github.com/go-kratos/kratos/v2/encoding"
 	kratosLog "github.com/go-kratos/kratos/v2/log"
 	kratosMiddleware "github.com/go-kratos/kratos/v2/middleware"
 	kratosRegistry "github.com/go-kratos/kratos/v2/registry"
@@ -17,6 +22,7 @@ import (
 	"github.com/go-kratos/kratos/v2/transport/grpc"
 	"github.com/go-kratos/kratos/v2/transport/http"
 	"github.com/google/wire"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 // App 应用结构体，包含所有依赖
@@ -25,11 +31,30 @@ type App struct {
 	Config    *config.Config
 	Logger    kratosLog.Logger
 	Servers   []transport.Server
+	Registry  *registry.Registry // 注册中心组件（未配置注册中心时为 nil）
 	Registrar kratosRegistry.Registrar
 	Discovery kratosRegistry.Discovery
+	Admin     *admin.Server // 管理服务器（未配置 server.admin.addr 时为 nil）
+	Health    *admin.Health
+	Clients   *ClientFactory
+	Lifecycle *Lifecycle
+}
+
+// Registers 业务服务注册
+// 业务代码通过 Wire 提供 Registers，将依赖注入后的 service 注册到 HTTP/gRPC 服务器
+type Registers struct {
+	HTTP []func(*http.Server) // HTTP 路由注册函数
+	GRPC []func(*grpc.Server) // gRPC 服务注册函数
 }
 
 // ProviderSet 是 Wire 的 Provider 集合
+// 业务代码可以将其与自己的 Provider 集合组合，由 Wire 注入 Config、Logger、Discovery、Lifecycle、ClientFactory 等底座组件：
+//
+//	func wireApp(service string, opts ...bootstrap.Option) (*bootstrap.App, func(), error) {
+//		panic(wire.Build(bootstrap.ProviderSet, data.ProviderSet, biz.ProviderSet, service.ProviderSet, server.ProviderSet))
+//	}
+//
+// 组合时业务代码需要提供 Registers（通常由 server.ProviderSet 提供）
 var ProviderSet = wire.NewSet(
 	// Options
 	NewOptions,
@@ -40,18 +65,31 @@ var ProviderSet = wire.NewSet(
 	// 日志相关
 	NewLogger,
 
+	// 生命周期
+	NewLifecycle,
+
 	// 中间件相关
+	middleware.NewInFlightTracker,
 	NewMiddlewares,
 
+	// 国际化
+	NewTranslator,
+
 	// 服务器相关
 	NewGRPCServer,
 	NewHTTPServer,
+	NewHealth,
+	NewAdminServer,
 	NewServers,
 
 	// 注册中心相关
+	NewRegistry,
 	NewRegistrar,
 	NewDiscovery,
 
+	// 客户端相关
+	NewClientFactory,
+
 	// Kratos App
 	NewKratosApp,
 
@@ -88,10 +126,12 @@ func NewLogger(service string, cfg *config.Config) (kratosLog.Logger, error) {
 }
 
 // NewMiddlewares 创建中间件列表 Provider
-func NewMiddlewares(cfg *config.Config, logger kratosLog.Logger, opts *options) []kratosMiddleware.Middleware {
+func NewMiddlewares(cfg *config.Config, logger kratosLog.Logger, tracker *middleware.InFlightTracker, opts *options) []kratosMiddleware.Middleware {
 	middlewares := []kratosMiddleware.Middleware{
-		middleware.Recovery(logger), // 最外层：panic 恢复（必须）
-		middleware.Metadata(),       // 元数据传递（必须，用于服务间通信）
+		middleware.Recovery(logger),  // 最外层：panic 恢复（必须）
+		middleware.InFlight(tracker), // 处理中请求统计（用于优雅关闭）
+		middleware.Metadata(),        // 元数据传递（必须，用于服务间通信）
+		middleware.RequestID(),       // 请求 ID（必须，用于错误响应与日志关联）
 	}
 
 	// Tracing 在 Logging 之前，确保日志中包含 trace 信息
@@ -116,11 +156,18 @@ func NewMiddlewares(cfg *config.Config, logger kratosLog.Logger, opts *options)
 }
 
 // NewGRPCServer 创建 gRPC 服务器 Provider
-func NewGRPCServer(cfg *config.Config, middlewares []kratosMiddleware.Middleware, opts *options) GRPCServer {
+func NewGRPCServer(cfg *config.Config, middlewares []kratosMiddleware.Middleware, translator *i18n.Translator, registers Registers, opts *options) GRPCServer {
 	if cfg.Server.GRPC.Addr == "" {
 		return nil
 	}
 
+	// 错误详情与错误消息翻译在最外层，覆盖所有中间件返回的错误
+	outer := []kratosMiddleware.Middleware{middleware.ErrorDetails()}
+	if translator != nil {
+		outer = append(outer, middleware.I18n(translator))
+	}
+	middlewares = append(outer, middlewares...)
+
 	grpcOpts := []grpc.ServerOption{
 		grpc.Address(cfg.Server.GRPC.Addr),
 		grpc.Middleware(middlewares...),
@@ -138,21 +185,44 @@ func NewGRPCServer(cfg *config.Config, middlewares []kratosMiddleware.Middleware
 	for _, register := range opts.grpcRegisters {
 		register(grpcSrv)
 	}
+	for _, register := range registers.GRPC {
+		register(grpcSrv)
+	}
 
 	return GRPCServer(grpcSrv)
 }
 
 // NewHTTPServer 创建 HTTP 服务器 Provider
-func NewHTTPServer(cfg *config.Config, middlewares []kratosMiddleware.Middleware, opts *options) HTTPServer {
+func NewHTTPServer(cfg *config.Config, middlewares []kratosMiddleware.Middleware, translator *i18n.Translator, registers Registers, opts *options) HTTPServer {
 	if cfg.Server.HTTP.Addr == "" {
 		return nil
 	}
 
+	responseOpts := []response.Option{
+		response.WithErrorMode(response.ErrorMode(cfg.Response.ErrorMode)),
+		response.WithProtoJSON(protojson.MarshalOptions{
+			UseProtoNames:   !cfg.Response.ProtoJSON.UseJSONNames,
+			UseEnumNumbers:  cfg.Response.ProtoJSON.UseEnumNumbers,
+			EmitUnpopulated: cfg.Response.ProtoJSON.EmitUnpopulated,
+		}),
+		response.WithRawOperations(cfg.Response.RawOperations...),
+		response.WithRawPaths(cfg.Response.RawPaths...),
+		response.WithTranslator(translator),
+	}
+	for _, name := range cfg.Response.Codecs {
+		codec := encoding.GetCodec(name)
+		if codec == nil {
+			fmt.Printf("WARN: unknown response codec: %s\n", name)
+			continue
+		}
+		responseOpts = append(responseOpts, response.WithCodec(codec))
+	}
+
 	httpOpts := []http.ServerOption{
 		http.Address(cfg.Server.HTTP.Addr),
 		http.Middleware(middlewares...),
-		http.ResponseEncoder(response.ResponseEncoder()), // 统一响应格式
-		http.ErrorEncoder(response.ErrorEncoder()),       // 统一错误格式
+		http.ResponseEncoder(response.ResponseEncoder(responseOpts...)), // 统一响应格式
+		http.ErrorEncoder(response.ErrorEncoder(responseOpts...)),       // 统一错误格式
 	}
 
 	// 配置超时（如果指定）
@@ -167,18 +237,63 @@ func NewHTTPServer(cfg *config.Config, middlewares []kratosMiddleware.Middleware
 	for _, register := range opts.httpRegisters {
 		register(httpSrv)
 	}
+	for _, register := range registers.HTTP {
+		register(httpSrv)
+	}
 
 	return HTTPServer(httpSrv)
 }
 
+// NewTranslator 创建错误消息翻译器 Provider
+// 未配置 i18n.dir 时返回 nil，不启用翻译
+func NewTranslator(cfg *config.Config) (*i18n.Translator, error) {
+	if cfg.I18n.Dir == "" {
+		return nil, nil
+	}
+	translator := i18n.New(cfg.I18n.DefaultLanguage)
+	if err := translator.LoadDir(cfg.I18n.Dir); err != nil {
+		return nil, fmt.Errorf("load i18n messages failed: %w", err)
+	}
+	return translator, nil
+}
+
 // GRPCServer gRPC 服务器类型别名，用于 Wire 依赖注入
 type GRPCServer transport.Server
 
 // HTTPServer HTTP 服务器类型别名，用于 Wire 依赖注入
 type HTTPServer transport.Server
 
+// NewHealth 创建健康状态 Provider
+// 配置了注册中心时，将注册中心连接加入就绪检查
+func NewHealth(reg *registry.Registry) *admin.Health {
+	health := admin.NewHealth()
+	if reg != nil {
+		health.AddCheck("registry", reg.Health)
+	}
+	return health
+}
+
+// NewAdminServer 创建管理服务器 Provider
+// 管理服务器独立监听，不经过业务中间件，也不会注册到注册中心
+func NewAdminServer(service string, cfg *config.Config, health *admin.Health) *admin.Server {
+	if cfg.Server.Admin.Addr == "" {
+		return nil
+	}
+
+	version := cfg.App.Version
+	if version == "" {
+		version = common.DefaultVersion
+	}
+
+	return admin.NewServer(cfg.Server.Admin.Addr,
+		admin.WithService(service, version),
+		admin.WithConfig(cfg),
+		admin.WithHealth(health),
+	)
+}
+
 // NewServers 创建服务器列表 Provider
-func NewServers(grpcSrv GRPCServer, httpSrv HTTPServer) []transport.Server {
+func NewServers(grpcSrv GRPCServer, httpSrv HTTPServer, adminSrv *admin.Server) []transport.Server {
 	var servers []transport.Server
 	if grpcSrv != nil {
 		servers = append(servers, transport.Server(grpcSrv))
@@ -186,24 +301,43 @@ func NewServers(grpcSrv GRPCServer, httpSrv HTTPServer) []transport.Server {
 	if httpSrv != nil {
 		servers = append(servers, transport.Server(httpSrv))
 	}
+	if adminSrv != nil {
+		servers = append(servers, adminSrv)
+	}
 	return servers
 }
 
+// NewRegistry 创建注册中心组件 Provider
+// 服务注册与服务发现共用同一个注册中心客户端，应用停止时关闭
+func NewRegistry(cfg *config.Config) (*registry.Registry, error) {
+	return registry.New(cfg.App.Discovery)
+}
+
 // NewRegistrar 创建服务注册中心 Provider
-func NewRegistrar(cfg *config.Config) (kratosRegistry.Registrar, error) {
-	return registry.NewRegistrar(cfg.App.Discovery)
+// 按 app.discovery.registration 配置启动重试或后台注册，并在实例丢失后自动重新注册
+func NewRegistrar(cfg *config.Config, logger kratosLog.Logger, reg *registry.Registry) kratosRegistry.Registrar {
+	r := reg.Registrar()
+	if r == nil {
+		return nil
+	}
+	r = newResilientRegistrar(r, reg, logger, cfg.App.Discovery.Registration)
+	// 预停止阶段会提前注销，保证注销只执行一次
+	return newOnceRegistrar(r)
 }
 
 // NewDiscovery 创建服务发现客户端 Provider
-func NewDiscovery(cfg *config.Config) (kratosRegistry.Discovery, error) {
-	return registry.NewDiscovery(cfg.App.Discovery)
+func NewDiscovery(reg *registry.Registry) kratosRegistry.Discovery {
+	return reg.Discovery()
 }
 
 // NewKratosApp 创建 Kratos App Provider
-func NewKratosApp(service string, cfg *config.Config, logger kratosLog.Logger, servers []transport.Server, registrar kratosRegistry.Registrar, opts *options) (*kratos.App, error) {
+// 返回的 cleanup 关闭生命周期钩子、客户端连接与注册中心客户端，正常停止时已由 AfterStop 关闭则不再重复关闭
+func NewKratosApp(service string, cfg *config.Config, logger kratosLog.Logger, servers []transport.Server, reg *registry.Registry, registrar kratosRegistry.Registrar, health *admin.Health, lc *Lifecycle, clients *ClientFactory, tracker *middleware.InFlightTracker, opts *options) (*kratos.App, func(), error) {
+	// App 的 context 携带本应用的客户端工厂，请求处理与生命周期钩子中可以使用 NewGRPCClient / NewHTTPClient
 	appOpts := []kratos.Option{
 		kratos.Name(service),
 		kratos.Logger(logger),
+		kratos.Context(NewClientContext(context.Background(), clients)),
 	}
 
 	// 配置版本
@@ -218,17 +352,21 @@ func NewKratosApp(service string, cfg *config.Config, logger kratosLog.Logger, s
 		appOpts = append(appOpts, kratos.StopTimeout(stopTimeout))
 	}
 
-	// 统一信号处理（优雅关闭）
-	appOpts = append(appOpts, kratos.Signal(os.Interrupt, os.Kill))
+	// 统一信号处理（优雅关闭）：SIGTERM 为 Kubernetes 停止 Pod 时发送的信号
+	appOpts = append(appOpts, kratos.Signal(syscall.SIGTERM, syscall.SIGINT))
 
 	// 配置服务元数据（如果指定）
 	if len(cfg.App.Metadata) > 0 {
 		appOpts = append(appOpts, kratos.Metadata(cfg.App.Metadata))
 	}
 
-	// 配置服务注册
+	// 配置服务注册，注册重试在 RegistrarTimeout 内进行
 	if registrar != nil {
-		appOpts = append(appOpts, kratos.Registrar(registrar))
+		var registration *config.Registration
+		if cfg.App.Discovery != nil {
+			registration = cfg.App.Discovery.Registration
+		}
+		appOpts = append(appOpts, kratos.Registrar(registrar), kratos.RegistrarTimeout(registrationTimeout(registration)))
 	}
 
 	// 添加服务器
@@ -236,20 +374,51 @@ func NewKratosApp(service string, cfg *config.Config, logger kratosLog.Logger, s
 		appOpts = append(appOpts, kratos.Server(servers...))
 	}
 
+	// 就绪状态：启动完成（含服务注册）后就绪
+	// 开始关闭时先进入预停止阶段：就绪检查失败、从注册中心注销、等待 preStopDelay 后再停止服务器
+	appOpts = append(appOpts,
+		kratos.AfterStart(func(context.Context) error {
+			health.SetReady(true)
+			return nil
+		}),
+		kratos.BeforeStop(preStop(logger, health, registrar, common.ParseTimeout(cfg.App.PreStopDelay))),
+	)
+
+	// 业务组件生命周期：服务器启动前启动，服务器停止后逆序关闭
+	// 启动失败（如服务注册失败）时 Kratos 不执行 AfterStop，由返回的 cleanup 关闭
+	closeComponents := newComponentCloser(lc, clients, reg)
+	appOpts = append(appOpts,
+		kratos.BeforeStart(lc.Start),
+		kratos.AfterStop(func(ctx context.Context) error {
+			reportInFlight(logger, tracker)
+			return closeComponents(ctx)
+		}),
+	)
+
 	// 添加业务代码传入的额外 App 选项
 	appOpts = append(appOpts, opts.appOpts...)
 
-	return kratos.New(appOpts...), nil
+	cleanup := func() {
+		if err := closeComponents(context.Background()); err != nil {
+			kratosLog.NewHelper(logger).Errorf("close components failed: %v", err)
+		}
+	}
+	return kratos.New(appOpts...), cleanup, nil
 }
 
 // NewApp 创建最终 App Provider
-func NewApp(app *kratos.App, cfg *config.Config, logger kratosLog.Logger, servers []transport.Server, registrar kratosRegistry.Registrar, discovery kratosRegistry.Discovery) *App {
+func NewApp(app *kratos.App, cfg *config.Config, logger kratosLog.Logger, servers []transport.Server, reg *registry.Registry, registrar kratosRegistry.Registrar, discovery kratosRegistry.Discovery, adminSrv *admin.Server, health *admin.Health, clients *ClientFactory, lc *Lifecycle) *App {
 	return &App{
 		App:       app,
 		Config:    cfg,
 		Logger:    logger,
 		Servers:   servers,
+		Registry:  reg,
 		Registrar: registrar,
 		Discovery: discovery,
+		Admin:     adminSrv,
+		Health:    health,
+		Clients:   clients,
+		Lifecycle: lc,
 	}
 }
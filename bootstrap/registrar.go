@@ -0,0 +1,252 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/addls/go-boot/common"
+	"github.com/addls/go-boot/config"
+	kratosLog "github.com/go-kratos/kratos/v2/log"
+	kratosRegistry "github.com/go-kratos/kratos/v2/registry"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 启动时的注册方式
+const (
+	registrationRetry    = "retry"    // 重试失败后启动失败（默认）
+	registrationFailFast = "failfast" // 只尝试一次
+	registrationAsync    = "async"    // 先提供服务，后台重试直到成功
+)
+
+// 服务注册默认配置
+const (
+	defaultRegisterRetries     = 5
+	defaultRegisterBackoff     = time.Second
+	defaultRegisterMaxBackoff  = 30 * time.Second
+	defaultRegistrationCheck   = 30 * time.Second
+	defaultRegistrationTimeout = time.Minute
+	registerAttemptTimeout     = 10 * time.Second
+)
+
+// registrationTimeout 启动时注册（含重试）的总超时，作为 Kratos 的 RegistrarTimeout
+func registrationTimeout(cfg *config.Registration) time.Duration {
+	if cfg != nil {
+		if d := common.ParseTimeout(cfg.Timeout); d > 0 {
+			return d
+		}
+	}
+	return defaultRegistrationTimeout
+}
+
+// 服务注册监控指标
+var (
+	registryRegistered = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "goboot_registry_registered",
+		Help: "Whether the service instance is registered to the registry (1) or not (0).",
+	}, []string{"service"})
+	registryRegisterTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goboot_registry_register_total",
+		Help: "Number of service registration attempts by result.",
+	}, []string{"service", "result"})
+	registryLostTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goboot_registry_instance_lost_total",
+		Help: "Number of times the service instance was found missing from the registry and re-registered.",
+	}, []string{"service"})
+)
+
+// resilientRegistrar 为服务注册增加启动重试、后台注册与丢失后自动重新注册
+// 注册成功后定期检查实例是否仍在注册中心（租约过期、会话失效、注册中心重建等），丢失时重新注册
+type resilientRegistrar struct {
+	kratosRegistry.Registrar
+	checker registrationChecker // 用于检查实例是否仍在注册中心，nil 时不检查
+	log     *kratosLog.Helper
+
+	mode          string
+	maxRetries    int
+	backoff       time.Duration
+	maxBackoff    time.Duration
+	checkInterval time.Duration
+
+	mu      sync.Mutex
+	running map[string]*registration
+}
+
+// registrationChecker 检查实例是否仍在注册中心
+type registrationChecker interface {
+	Registered(ctx context.Context, ins *kratosRegistry.ServiceInstance) bool
+}
+
+// registration 单个实例的后台注册与保活任务
+type registration struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newResilientRegistrar(r kratosRegistry.Registrar, checker registrationChecker, logger kratosLog.Logger, cfg *config.Registration) *resilientRegistrar {
+	rr := &resilientRegistrar{
+		Registrar:     r,
+		checker:       checker,
+		log:           kratosLog.NewHelper(logger),
+		mode:          registrationRetry,
+		maxRetries:    defaultRegisterRetries,
+		backoff:       defaultRegisterBackoff,
+		maxBackoff:    defaultRegisterMaxBackoff,
+		checkInterval: defaultRegistrationCheck,
+		running:       make(map[string]*registration),
+	}
+	if cfg == nil {
+		return rr
+	}
+
+	// 取值已由 config.Validate 校验
+	if cfg.Mode != "" {
+		rr.mode = cfg.Mode
+	}
+	if cfg.MaxRetries > 0 {
+		rr.maxRetries = cfg.MaxRetries
+	}
+	if d := common.ParseTimeout(cfg.Backoff); d > 0 {
+		rr.backoff = d
+	}
+	if d := common.ParseTimeout(cfg.MaxBackoff); d > 0 {
+		rr.maxBackoff = d
+	}
+	if cfg.CheckInterval != "" {
+		// "0" 关闭检查
+		rr.checkInterval = common.ParseTimeout(cfg.CheckInterval)
+	}
+	return rr
+}
+
+// Register 注册服务实例
+// async 模式立即返回，在后台重试直到成功；其他模式按配置重试，成功后启动后台保活检查
+func (r *resilientRegistrar) Register(ctx context.Context, ins *kratosRegistry.ServiceInstance) error {
+	if r.mode == registrationAsync {
+		r.start(ins, func(ctx context.Context) {
+			if r.register(ctx, ins, -1) == nil {
+				r.keepAlive(ctx, ins)
+			}
+		})
+		return nil
+	}
+
+	retries := r.maxRetries
+	if r.mode == registrationFailFast {
+		retries = 0
+	}
+	// 重试受调用方（Kratos RegistrarTimeout）的截止时间与取消约束
+	if err := r.register(ctx, ins, retries); err != nil {
+		return err
+	}
+	r.start(ins, func(ctx context.Context) {
+		r.keepAlive(ctx, ins)
+	})
+	return nil
+}
+
+// Deregister 停止后台注册与保活检查后注销服务实例
+func (r *resilientRegistrar) Deregister(ctx context.Context, ins *kratosRegistry.ServiceInstance) error {
+	r.stop(ins.ID)
+	registryRegistered.WithLabelValues(ins.Name).Set(0)
+	return r.Registrar.Deregister(ctx, ins)
+}
+
+// register 注册服务实例，失败后按指数退避重试，retries 小于 0 时一直重试直到 ctx 结束
+// 下次重试会超过 ctx 的截止时间或 ctx 被取消时停止重试，返回最后一次注册的错误
+func (r *resilientRegistrar) register(ctx context.Context, ins *kratosRegistry.ServiceInstance, retries int) error {
+	backoff := r.backoff
+	for attempt := 1; ; attempt++ {
+		actx, cancel := context.WithTimeout(ctx, registerAttemptTimeout)
+		err := r.Registrar.Register(actx, ins)
+		cancel()
+		if err == nil {
+			registryRegisterTotal.WithLabelValues(ins.Name, "success").Inc()
+			registryRegistered.WithLabelValues(ins.Name).Set(1)
+			r.log.Infof("service instance %s registered", ins.ID)
+			return nil
+		}
+		registryRegisterTotal.WithLabelValues(ins.Name, "failure").Inc()
+		registryRegistered.WithLabelValues(ins.Name).Set(0)
+		if retries >= 0 && attempt > retries {
+			return fmt.Errorf("register service instance %s failed after %d attempt(s): %w", ins.ID, attempt, err)
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			return fmt.Errorf("register service instance %s failed after %d attempt(s), no time left to retry: %w", ins.ID, attempt, err)
+		}
+
+		r.log.Warnf("register service instance %s failed (attempt %d), retrying in %s: %v", ins.ID, attempt, backoff, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("register service instance %s canceled after %d attempt(s): %w", ins.ID, attempt, err)
+		}
+		backoff = min(backoff*2, r.maxBackoff)
+	}
+}
+
+// keepAlive 定期检查实例是否仍在注册中心，丢失时重新注册
+func (r *resilientRegistrar) keepAlive(ctx context.Context, ins *kratosRegistry.ServiceInstance) {
+	if r.checker == nil || r.checkInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(r.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		cctx, cancel := context.WithTimeout(ctx, registerAttemptTimeout)
+		registered := r.checker.Registered(cctx, ins)
+		cancel()
+		if registered {
+			continue
+		}
+
+		r.log.Warnf("service instance %s not found in registry, re-registering", ins.ID)
+		registryLostTotal.WithLabelValues(ins.Name).Inc()
+		registryRegistered.WithLabelValues(ins.Name).Set(0)
+		// 先注销以清理残留的租约与心跳，注册失败时等待下个检查周期
+		dctx, cancel := context.WithTimeout(ctx, registerAttemptTimeout)
+		_ = r.Registrar.Deregister(dctx, ins)
+		cancel()
+		if err := r.register(ctx, ins, 0); err != nil && ctx.Err() == nil {
+			r.log.Warnf("re-register service instance %s failed: %v", ins.ID, err)
+		}
+	}
+}
+
+// start 启动实例的后台任务，替换已有任务
+func (r *resilientRegistrar) start(ins *kratosRegistry.ServiceInstance, fn func(context.Context)) {
+	r.stop(ins.ID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reg := &registration{cancel: cancel, done: make(chan struct{})}
+	r.mu.Lock()
+	r.running[ins.ID] = reg
+	r.mu.Unlock()
+
+	go func() {
+		defer close(reg.done)
+		fn(ctx)
+	}()
+}
+
+// stop 停止实例的后台任务并等待退出，避免与注销并发执行注册
+func (r *resilientRegistrar) stop(id string) {
+	r.mu.Lock()
+	reg := r.running[id]
+	delete(r.running, id)
+	r.mu.Unlock()
+	if reg != nil {
+		reg.cancel()
+		<-reg.done
+	}
+}
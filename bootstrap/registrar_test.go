@@ -0,0 +1,184 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/addls/go-boot/config"
+	kratosLog "github.com/go-kratos/kratos/v2/log"
+	kratosRegistry "github.com/go-kratos/kratos/v2/registry"
+)
+
+// fakeRegistrar 前 failures 次注册失败的注册中心，同时实现 registrationChecker
+type fakeRegistrar struct {
+	mu         sync.Mutex
+	failures   int
+	attempts   int
+	registered map[string]bool
+}
+
+func newFakeRegistrar(failures int) *fakeRegistrar {
+	return &fakeRegistrar{failures: failures, registered: make(map[string]bool)}
+}
+
+func (f *fakeRegistrar) Register(_ context.Context, ins *kratosRegistry.ServiceInstance) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts++
+	if f.failures < 0 || f.attempts <= f.failures {
+		return errors.New("registry unavailable")
+	}
+	f.registered[ins.ID] = true
+	return nil
+}
+
+func (f *fakeRegistrar) Deregister(_ context.Context, ins *kratosRegistry.ServiceInstance) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.registered, ins.ID)
+	return nil
+}
+
+func (f *fakeRegistrar) Registered(_ context.Context, ins *kratosRegistry.ServiceInstance) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.registered[ins.ID]
+}
+
+// lose 模拟实例从注册中心丢失（如租约过期）
+func (f *fakeRegistrar) lose(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.registered, id)
+}
+
+func (f *fakeRegistrar) state(id string) (attempts int, registered bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attempts, f.registered[id]
+}
+
+// eventually 等待条件成立
+func eventually(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestResilientRegistrar(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         string
+		failures     int // 失败次数，-1 表示一直失败
+		timeout      time.Duration
+		backoff      string
+		wantErr      bool
+		wantAttempts int
+	}{
+		{name: "retry succeeds", mode: "retry", failures: 2, wantAttempts: 3},
+		{name: "retry exhausted", mode: "retry", failures: -1, wantErr: true, wantAttempts: 4},
+		{name: "retry stops at deadline", mode: "retry", failures: -1, timeout: 50 * time.Millisecond, backoff: "1s", wantErr: true, wantAttempts: 1},
+		{name: "failfast", mode: "failfast", failures: 1, wantErr: true, wantAttempts: 1},
+		{name: "failfast succeeds", mode: "failfast", wantAttempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backoff := tt.backoff
+			if backoff == "" {
+				backoff = "1ms"
+			}
+			fake := newFakeRegistrar(tt.failures)
+			r := newResilientRegistrar(fake, fake, kratosLog.DefaultLogger, &config.Registration{
+				Mode: tt.mode, MaxRetries: 3, Backoff: backoff, CheckInterval: "0",
+			})
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			ins := &kratosRegistry.ServiceInstance{ID: "order-1", Name: "order"}
+
+			start := time.Now()
+			err := r.Register(ctx, ins)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("Register() took %s", elapsed)
+			}
+			attempts, registered := fake.state(ins.ID)
+			if attempts != tt.wantAttempts || registered == tt.wantErr {
+				t.Fatalf("attempts = %d, registered = %v, want %d, %v", attempts, registered, tt.wantAttempts, !tt.wantErr)
+			}
+			if err := r.Deregister(context.Background(), ins); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestResilientRegistrarAsync(t *testing.T) {
+	fake := newFakeRegistrar(3)
+	r := newResilientRegistrar(fake, fake, kratosLog.DefaultLogger, &config.Registration{Mode: "async", Backoff: "1ms", CheckInterval: "0"})
+	ins := &kratosRegistry.ServiceInstance{ID: "order-1", Name: "order"}
+
+	// 立即返回，后台重试直到注册成功
+	if err := r.Register(context.Background(), ins); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		_, registered := fake.state(ins.ID)
+		return registered
+	})
+	if err := r.Deregister(context.Background(), ins); err != nil {
+		t.Fatal(err)
+	}
+	if _, registered := fake.state(ins.ID); registered {
+		t.Fatal("instance should be deregistered")
+	}
+
+	// 注销后停止后台重试
+	fake = newFakeRegistrar(-1)
+	r = newResilientRegistrar(fake, fake, kratosLog.DefaultLogger, &config.Registration{Mode: "async", Backoff: "1ms", MaxBackoff: "1ms", CheckInterval: "0"})
+	if err := r.Register(context.Background(), ins); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		attempts, _ := fake.state(ins.ID)
+		return attempts > 1
+	})
+	if err := r.Deregister(context.Background(), ins); err != nil {
+		t.Fatal(err)
+	}
+	attempts, _ := fake.state(ins.ID)
+	time.Sleep(20 * time.Millisecond)
+	if after, _ := fake.state(ins.ID); after != attempts {
+		t.Fatalf("attempts after Deregister = %d, want %d", after, attempts)
+	}
+}
+
+func TestResilientRegistrarReRegister(t *testing.T) {
+	fake := newFakeRegistrar(0)
+	r := newResilientRegistrar(fake, fake, kratosLog.DefaultLogger, &config.Registration{CheckInterval: "10ms"})
+	ins := &kratosRegistry.ServiceInstance{ID: "order-1", Name: "order"}
+	if err := r.Register(context.Background(), ins); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Deregister(context.Background(), ins)
+
+	// 实例丢失后由保活检查重新注册
+	fake.lose(ins.ID)
+	eventually(t, func() bool {
+		attempts, registered := fake.state(ins.ID)
+		return attempts == 2 && registered
+	})
+}
@@ -0,0 +1,119 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/addls/go-boot/admin"
+	"github.com/addls/go-boot/middleware"
+	"github.com/addls/go-boot/registry"
+	"github.com/go-kratos/kratos/v2"
+	kratosLog "github.com/go-kratos/kratos/v2/log"
+	kratosRegistry "github.com/go-kratos/kratos/v2/registry"
+)
+
+// deregisterTimeout 预停止阶段注销服务的超时
+const deregisterTimeout = 10 * time.Second
+
+// onceRegistrar 保证同一实例只注销一次
+// 预停止阶段会提前注销服务，Kratos 在停止时再次注销时直接返回
+type onceRegistrar struct {
+	kratosRegistry.Registrar
+
+	mu           sync.Mutex
+	deregistered map[string]bool
+}
+
+func newOnceRegistrar(r kratosRegistry.Registrar) *onceRegistrar {
+	return &onceRegistrar{Registrar: r, deregistered: make(map[string]bool)}
+}
+
+// Register 注册服务实例
+func (r *onceRegistrar) Register(ctx context.Context, ins *kratosRegistry.ServiceInstance) error {
+	r.mu.Lock()
+	delete(r.deregistered, ins.ID)
+	r.mu.Unlock()
+	return r.Registrar.Register(ctx, ins)
+}
+
+// Deregister 注销服务实例，重复注销直接返回
+func (r *onceRegistrar) Deregister(ctx context.Context, ins *kratosRegistry.ServiceInstance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.deregistered[ins.ID] {
+		return nil
+	}
+	if err := r.Registrar.Deregister(ctx, ins); err != nil {
+		return err
+	}
+	r.deregistered[ins.ID] = true
+	return nil
+}
+
+// preStop 返回预停止钩子
+// 依次：就绪检查失败 -> 从注册中心注销 -> 等待 delay（让负载均衡与调用方摘除本实例）-> 停止服务器
+func preStop(logger kratosLog.Logger, health *admin.Health, registrar kratosRegistry.Registrar, delay time.Duration) func(context.Context) error {
+	helper := kratosLog.NewHelper(logger)
+	return func(ctx context.Context) error {
+		health.SetReady(false)
+
+		if registrar != nil {
+			if info, ok := kratos.FromContext(ctx); ok {
+				rctx, cancel := context.WithTimeout(ctx, deregisterTimeout)
+				err := registrar.Deregister(rctx, &kratosRegistry.ServiceInstance{
+					ID:        info.ID(),
+					Name:      info.Name(),
+					Version:   info.Version(),
+					Metadata:  info.Metadata(),
+					Endpoints: info.Endpoint(),
+				})
+				cancel()
+				if err != nil {
+					helper.Warnf("pre-stop deregister failed: %v", err)
+				}
+			}
+		}
+
+		if delay > 0 {
+			helper.Infof("pre-stop: waiting %s before stopping servers", delay)
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+			}
+		}
+		return nil
+	}
+}
+
+// newComponentCloser 返回关闭业务组件的函数，多次调用只关闭一次
+// 客户端连接在所有生命周期钩子关闭后再关闭，保证关闭过程中仍可调用下游服务
+// 注册中心客户端最后关闭（此时服务已注销，客户端连接也不再进行服务发现）
+func newComponentCloser(lc *Lifecycle, clients *ClientFactory, reg *registry.Registry) func(context.Context) error {
+	var once sync.Once
+	return func(ctx context.Context) error {
+		var err error
+		once.Do(func() {
+			err = errors.Join(lc.Stop(ctx), clients.Close(), reg.Close())
+		})
+		return err
+	}
+}
+
+// reportInFlight 服务器停止后仍有未完成的请求时（通常是达到 stopTimeout 被强制关闭），输出请求摘要
+func reportInFlight(logger kratosLog.Logger, tracker *middleware.InFlightTracker) {
+	if tracker.Count() == 0 {
+		return
+	}
+	helper := kratosLog.NewHelper(logger)
+	for operation, n := range tracker.Snapshot() {
+		helper.Log(kratosLog.LevelWarn,
+			"msg", "request still in flight after stop timeout",
+			"operation", operation,
+			"count", n,
+		)
+	}
+}
@@ -0,0 +1,72 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/addls/go-boot/admin"
+	"github.com/addls/go-boot/config"
+	"github.com/addls/go-boot/middleware"
+	"github.com/go-kratos/kratos/v2"
+	kratosLog "github.com/go-kratos/kratos/v2/log"
+	kratosRegistry "github.com/go-kratos/kratos/v2/registry"
+)
+
+// failingRegistrar 注册总是失败的注册中心
+type failingRegistrar struct{}
+
+func (failingRegistrar) Register(context.Context, *kratosRegistry.ServiceInstance) error {
+	return errors.New("registry unavailable")
+}
+
+func (failingRegistrar) Deregister(context.Context, *kratosRegistry.ServiceInstance) error {
+	return nil
+}
+
+func TestKratosAppCleanup(t *testing.T) {
+	tests := []struct {
+		name      string
+		registrar kratosRegistry.Registrar
+		wantErr   bool
+	}{
+		// 服务注册失败时 Kratos 不执行 AfterStop，由 cleanup 关闭钩子
+		{name: "register failed", registrar: failingRegistrar{}, wantErr: true},
+		// 正常停止时由 AfterStop 关闭钩子，cleanup 不再重复关闭
+		{name: "normal stop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stops atomic.Int32
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			opts := NewOptions(
+				WithHooks(Hook{Name: "db", OnStop: func(context.Context) error {
+					stops.Add(1)
+					return nil
+				}}),
+				WithAppOptions(kratos.Context(ctx), kratos.AfterStart(func(context.Context) error {
+					cancel()
+					return nil
+				})),
+			)
+			cfg := &config.Config{}
+			logger := kratosLog.DefaultLogger
+			lc := NewLifecycle(logger, opts)
+			clients := NewClientFactory(cfg, logger, nil, opts)
+
+			app, cleanup, err := NewKratosApp("test", cfg, logger, nil, nil, tt.registrar, admin.NewHealth(), lc, clients, middleware.NewInFlightTracker(), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := app.Run(); (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			cleanup()
+			if got := stops.Load(); got != 1 {
+				t.Fatalf("hook stopped %d times, want 1", got)
+			}
+		})
+	}
+}
@@ -9,7 +9,9 @@ import (
 
 // InitializeApp 初始化应用的所有依赖
 // Wire 会根据 ProviderSet 自动生成依赖注入代码
-func InitializeApp(service string, opts ...Option) (*App, error) {
-	wire.Build(ProviderSet)
-	return nil, nil
+// 业务服务通过 Option 注册（WithHTTPRegister / WithGRPCRegister），因此这里提供空的 Registers
+// 返回的 cleanup 在启动失败时关闭已启动的生命周期钩子、客户端连接与注册中心客户端
+func InitializeApp(service string, opts ...Option) (*App, func(), error) {
+	wire.Build(ProviderSet, wire.Struct(new(Registers)))
+	return nil, nil, nil
 }
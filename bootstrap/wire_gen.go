@@ -6,36 +6,52 @@
 
 package bootstrap
 
+import (
+	"github.com/addls/go-boot/middleware"
+)
+
 // Injectors from wire.go:
 
 // InitializeApp 初始化应用的所有依赖
 // Wire 会根据 ProviderSet 自动生成依赖注入代码
-func InitializeApp(service string, opts ...Option) (*App, error) {
+// 业务服务通过 Option 注册（WithHTTPRegister / WithGRPCRegister），因此这里提供空的 Registers
+// 返回的 cleanup 在启动失败时关闭已启动的生命周期钩子、客户端连接与注册中心客户端
+func InitializeApp(service string, opts ...Option) (*App, func(), error) {
 	bootstrapOptions := NewOptions(opts...)
 	config, err := NewConfig(service, bootstrapOptions)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	logger, err := NewLogger(service, config)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	v := NewMiddlewares(config, logger, bootstrapOptions)
-	grpcServer := NewGRPCServer(config, v, bootstrapOptions)
-	httpServer := NewHTTPServer(config, v, bootstrapOptions)
-	v2 := NewServers(grpcServer, httpServer)
-	registrar, err := NewRegistrar(config)
+	inFlightTracker := middleware.NewInFlightTracker()
+	v := NewMiddlewares(config, logger, inFlightTracker, bootstrapOptions)
+	translator, err := NewTranslator(config)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	app, err := NewKratosApp(service, config, logger, v2, registrar, bootstrapOptions)
+	registers := Registers{}
+	grpcServer := NewGRPCServer(config, v, translator, registers, bootstrapOptions)
+	httpServer := NewHTTPServer(config, v, translator, registers, bootstrapOptions)
+	registry, err := NewRegistry(config)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	discovery, err := NewDiscovery(config)
+	health := NewHealth(registry)
+	server := NewAdminServer(service, config, health)
+	v2 := NewServers(grpcServer, httpServer, server)
+	registrar := NewRegistrar(config, logger, registry)
+	lifecycle := NewLifecycle(logger, bootstrapOptions)
+	discovery := NewDiscovery(registry)
+	clientFactory := NewClientFactory(config, logger, discovery, bootstrapOptions)
+	app, cleanup, err := NewKratosApp(service, config, logger, v2, registry, registrar, health, lifecycle, clientFactory, inFlightTracker, bootstrapOptions)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	bootstrapApp := NewApp(app, config, logger, v2, registrar, discovery)
-	return bootstrapApp, nil
+	bootstrapApp := NewApp(app, config, logger, v2, registry, registrar, discovery, server, health, clientFactory, lifecycle)
+	return bootstrapApp, func() {
+		cleanup()
+	}, nil
 }
@@ -3,11 +3,14 @@ package main
 import (
 	"bufio"
 	"embed"
+	"flag"
 	"fmt"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime/debug"
+	"sort"
 	"strings"
 )
 
@@ -17,10 +20,27 @@ import (
 var templatesFS embed.FS
 
 var (
-	Version      = "v0.1.3"
+	Version      = "v0.2.0"
 	ProtoVersion = "v0.1.0"
 )
 
+// modulePath go-boot 的模块路径
+const modulePath = "github.com/addls/go-boot"
+
+// goBootVersion 新项目依赖的 go-boot 版本
+// 通过 go install ...@<version> 安装时使用构建信息中的模块版本，与 CLI 自身的代码一致；
+// 从源码构建（(devel) 或带 +dirty 等构建后缀的本地版本）时回退到 Version，released 为 false
+func goBootVersion() (version string, released bool) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Path != modulePath {
+		return Version, false
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" && !strings.Contains(v, "+") {
+		return v, true
+	}
+	return Version, false
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		printHelp()
@@ -30,8 +50,12 @@ func main() {
 	switch os.Args[1] {
 	case "init":
 		runInit()
+	case "new":
+		runNew()
 	case "api":
 		runAPI()
+	case "registry":
+		runRegistry()
 	case "version":
 		printVersion()
 	default:
@@ -45,8 +69,24 @@ func main() {
 // --------------------
 
 func runInit() {
+	flags := flag.NewFlagSet("init", flag.ExitOnError)
+	var templates []string
+	flags.Func("template", "custom template directory or git url, layered over the built-in templates (repeatable)", func(s string) error {
+		templates = append(templates, s)
+		return nil
+	})
+	_ = flags.Parse(os.Args[2:])
+
 	fmt.Println("[go-boot] init project")
 
+	// 先准备模板，自定义模板不可用时不做任何修改
+	layers, cleanup, err := templateLayers(templates)
+	if err != nil {
+		fmt.Printf("[go-boot] error: %v\n", err)
+		os.Exit(1)
+	}
+	defer cleanup()
+
 	// 初始化 go.mod（如果不存在）
 	initGoMod()
 
@@ -62,8 +102,8 @@ func runInit() {
 	// 创建标准目录结构
 	createStandardDirs()
 
-	// 复制 templates 目录下的所有文件到当前目录（包括 main.go）
-	copyTemplates()
+	// 复制内置模板与自定义模板到当前目录（包括 main.go）
+	copyTemplates(layers)
 
 	// 安装 go-boot 依赖（在生成 main.go 之后，因为它会导入 go-boot）
 	installGoBoot()
@@ -148,7 +188,8 @@ func runAPI() {
 }
 
 func printVersion() {
-	fmt.Printf("go-boot version: %s\n", Version)
+	version, _ := goBootVersion()
+	fmt.Printf("go-boot version: %s\n", version)
 	fmt.Printf("proto rules:     %s\n", ProtoVersion)
 }
 
@@ -157,11 +198,13 @@ func printVersion() {
 // --------------------
 
 func printHelp() {
-	fmt.Println(`go-boot - minimal enterprise bootstrap cli
+	fmt.Print(`go-boot - minimal enterprise bootstrap cli
 
 Usage:
+  go-boot new        create a new project in a new directory
   go-boot init       initialize project
   go-boot api        generate proto api code
+  go-boot registry   inspect services in the registry (list|get|watch)
   go-boot version    show version
 `)
 }
@@ -230,13 +273,14 @@ func initGoMod() {
 func installGoBoot() {
 	fmt.Println("[go-boot] installing go-boot...")
 
-	// 执行 go get
-	cmd := exec.Command("go", "get", "github.com/addls/go-boot")
+	// 执行 go get，使用与模板一致的版本
+	version, _ := goBootVersion()
+	cmd := exec.Command("go", "get", modulePath+"@"+version)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		fmt.Printf("[go-boot] warning: failed to install go-boot: %v\n", err)
-		fmt.Println("[go-boot] you can manually run: go get github.com/addls/go-boot")
+		fmt.Printf("[go-boot] you can manually run: go get %s@%s\n", modulePath, version)
 		return
 	}
 	fmt.Println("[go-boot] go-boot installed successfully")
@@ -374,13 +418,13 @@ func getServiceName() string {
 	return "service-user"
 }
 
-func copyTemplates() {
+func copyTemplates(layers []fs.FS) {
 	fmt.Println("[go-boot] copying template files...")
 
-	moduleName := getModuleName()
+	p := newProject(getModuleName(), getServiceName())
 
-	// 从嵌入的文件系统复制所有文件
-	if err := copyEmbeddedFiles(templatesFS, "templates", ".", moduleName); err != nil {
+	// 依次叠加内置模板与自定义模板后复制
+	if err := copyTemplateFiles(layers, ".", p); err != nil {
 		fmt.Printf("[go-boot] warning: failed to copy template files: %v\n", err)
 		return
 	}
@@ -388,70 +432,63 @@ func copyTemplates() {
 	fmt.Println("[go-boot] template files copied successfully")
 }
 
-func copyEmbeddedFiles(embedFS embed.FS, srcDir, dstDir string, moduleName string) error {
-	return fs.WalkDir(embedFS, srcDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// 计算目标路径（去掉 templates 前缀）
-		relPath, err := filepath.Rel("templates", path)
-		if err != nil {
-			return err
-		}
-
-		// 对于所有 .tpl 文件，去掉 .tpl 后缀
-		targetRelPath := relPath
-		if strings.HasSuffix(relPath, ".tpl") {
-			targetRelPath = strings.TrimSuffix(relPath, ".tpl")
-		}
+// copyTemplateFiles 按顺序叠加模板层并渲染到目标目录
+// 后面的模板层覆盖前面相同目标路径的文件，目标目录中已存在的文件跳过
+func copyTemplateFiles(layers []fs.FS, dstDir string, p *project) error {
+	files, err := collectTemplateFiles(layers)
+	if err != nil {
+		return err
+	}
 
-		targetPath := filepath.Join(dstDir, targetRelPath)
+	targets := make([]string, 0, len(files))
+	for target := range files {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
 
-		if d.IsDir() {
-			// 创建目录
-			return os.MkdirAll(targetPath, 0755)
+	for _, targetRelPath := range targets {
+		// 跳过条件不满足的文件（如未启用 HTTP 时的 http.go）
+		if !p.included(targetRelPath) {
+			continue
 		}
 
 		// 如果文件已存在，跳过
+		targetPath := filepath.Join(dstDir, filepath.FromSlash(targetRelPath))
 		if _, err := os.Stat(targetPath); err == nil {
-			return nil
-		}
-
-		// 确保目标目录存在
-		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-			return fmt.Errorf("create directory failed: %w", err)
+			continue
 		}
 
-		// 读取嵌入的文件
-		data, err := embedFS.ReadFile(path)
+		// 读取模板文件
+		file := files[targetRelPath]
+		data, err := fs.ReadFile(file.fsys, file.path)
 		if err != nil {
-			return fmt.Errorf("read embedded file failed: %w", err)
+			return fmt.Errorf("read template file failed: %w", err)
 		}
 
-		// 如果是模板文件（.go.tpl 或 .proto），替换模板变量
-		content := string(data)
-		if strings.HasSuffix(relPath, ".tpl") {
-			// 统计 %s 的数量，用于 fmt.Sprintf
-			count := strings.Count(content, "%s")
-			if count > 0 {
-				args := make([]interface{}, count)
-				// 所有 %s 都替换为模块名
-				for i := range args {
-					args[i] = moduleName
-				}
-				content = fmt.Sprintf(content, args...)
+		// .tpl 文件使用 text/template 按项目参数渲染，其他文件原样复制
+		// 渲染结果为空的文件不生成，覆盖为空模板即可去掉内置文件
+		if strings.HasSuffix(file.path, ".tpl") {
+			if data, err = renderTemplate(file.path, string(data), p); err != nil {
+				return err
+			}
+			if len(data) == 0 {
+				continue
 			}
 		}
 
+		// 确保目标目录存在
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return fmt.Errorf("create directory failed: %w", err)
+		}
+
 		// 写入目标文件
-		if err := os.WriteFile(targetPath, []byte(content), 0644); err != nil {
+		if err := os.WriteFile(targetPath, data, 0644); err != nil {
 			return fmt.Errorf("write file failed: %w", err)
 		}
 
 		fmt.Printf("[go-boot] copied %s\n", targetRelPath)
-		return nil
-	})
+	}
+	return nil
 }
 
 // --------------------
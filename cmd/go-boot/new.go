@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runNew 在新目录中创建项目
+// 只生成文件，不执行 go get、go install 等依赖网络的步骤
+func runNew() {
+	flags := flag.NewFlagSet("new", flag.ExitOnError)
+	module := flags.String("module", "", "module path (default base name of <dir>)")
+	name := flags.String("name", "", "service name (default base name of <dir>)")
+	enableHTTP := flags.Bool("http", true, "enable HTTP server")
+	enableGRPC := flags.Bool("grpc", true, "enable gRPC server")
+	registryType := flags.String("registry", "", "registry type: "+strings.Join(registryTypes, ", "))
+	features := flags.String("features", "", "comma separated features: "+strings.Join(featureNames, ", "))
+	var templates []string
+	flags.Func("template", "custom template directory or git url, layered over the built-in templates (repeatable)", func(s string) error {
+		templates = append(templates, s)
+		return nil
+	})
+	flags.Usage = func() {
+		fmt.Println("Usage:\n  go-boot new <dir> [flags]\n\nFlags:")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(os.Args[2:])
+	// 支持把选项写在目录之后
+	dir := flags.Arg(0)
+	if flags.NArg() > 1 {
+		_ = flags.Parse(flags.Args()[1:])
+	}
+	if dir == "" {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	if *module == "" {
+		*module = filepath.Base(dir)
+	}
+	if *name == "" {
+		*name = filepath.Base(dir)
+	}
+	p := newProject(*module, *name)
+	p.HTTP = *enableHTTP
+	p.GRPC = *enableGRPC
+	p.Registry = *registryType
+	for _, f := range strings.Split(*features, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			p.Features = append(p.Features, f)
+		}
+	}
+	if err := p.validate(); err != nil {
+		fmt.Printf("[go-boot] error: %v\n", err)
+		os.Exit(1)
+	}
+
+	layers, cleanup, err := templateLayers(templates)
+	if err != nil {
+		fmt.Printf("[go-boot] error: %v\n", err)
+		os.Exit(1)
+	}
+	err = createProject(dir, p, layers)
+	cleanup()
+	if err != nil {
+		fmt.Printf("[go-boot] error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("[go-boot] project %s created in %s\n", p.Service, dir)
+	if _, released := goBootVersion(); !released {
+		// 从源码构建的 CLI 对应的 go-boot 版本可能尚未发布
+		fmt.Printf(`[go-boot] warning: this go-boot was built from source, go.mod requires github.com/addls/go-boot %s which may not be published yet.
+If "go mod tidy" cannot find it, point go.mod at your go-boot checkout first:
+  go mod edit -replace github.com/addls/go-boot=/path/to/go-boot
+`, p.GoBootVersion)
+	}
+	fmt.Printf(`
+Next steps:
+  cd %s
+  go mod tidy      # download dependencies (github.com/addls/go-boot %s)
+  go build .       # compile the service, then run it with your config
+
+Run "go-boot init" in the project to install protoc plugins before "go-boot api".
+`, dir, p.GoBootVersion)
+}
+
+// createProject 创建项目目录并按项目参数渲染模板文件（包括 go.mod）
+func createProject(dir string, p *project, layers []fs.FS) error {
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("directory %s already exists and is not empty", dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, d := range []string{"internal/service", "internal/data"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			return err
+		}
+	}
+	return copyTemplateFiles(layers, dir, p)
+}
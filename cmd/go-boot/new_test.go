@@ -0,0 +1,44 @@
+package main
+
+import (
+	"io/fs"
+	"os/exec"
+	"testing"
+)
+
+// TestNewProjectBuilds 生成项目后不使用 replace，依赖 go.mod 中的 go-boot 版本编译
+// 确保模板使用的 API 在该版本中已经发布；发布新版本前需要把 Version 更新为即将发布的版本
+func TestNewProjectBuilds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("downloads go-boot from the module proxy")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	builtin, err := fs.Sub(templatesFS, "templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	p := newProject("example.com/demo", "demo")
+	p.Registry = "etcd"
+	p.Features = featureNames
+	if err := copyTemplateFiles([]fs.FS{builtin}, dir, p); err != nil {
+		t.Fatal(err)
+	}
+
+	download := exec.Command("go", "mod", "download", modulePath+"@"+p.GoBootVersion)
+	download.Dir = dir
+	if out, err := download.CombinedOutput(); err != nil {
+		// 区分版本未发布与无法访问模块代理
+		list := exec.Command("go", "list", "-m", "-versions", modulePath)
+		list.Dir = dir
+		if _, listErr := list.CombinedOutput(); listErr != nil {
+			t.Skipf("module proxy unavailable: %v", listErr)
+		}
+		t.Fatalf("go.mod requires %s %s which is not published: %v\n%s", modulePath, p.GoBootVersion, err, out)
+	}
+	run(t, dir, "go", "mod", "tidy")
+	run(t, dir, "go", "build", "./...")
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/addls/go-boot/config"
+	"github.com/addls/go-boot/registry"
+	kratosRegistry "github.com/go-kratos/kratos/v2/registry"
+)
+
+// registryTimeout registry list / get 的查询超时
+const registryTimeout = 10 * time.Second
+
+// runRegistry 读取项目配置中的 app.discovery，查看注册中心中的服务与实例
+func runRegistry() {
+	if len(os.Args) < 3 {
+		printRegistryHelp()
+		os.Exit(1)
+	}
+	sub := os.Args[2]
+	if sub != "list" && sub != "get" && sub != "watch" {
+		printRegistryHelp()
+		os.Exit(1)
+	}
+
+	flags := flag.NewFlagSet("registry "+sub, flag.ExitOnError)
+	configFile := flags.String("config", "", "config file path (default ./config.yaml or ./configs/config.yaml)")
+	jsonOutput := flags.Bool("json", false, "print as JSON")
+	_ = flags.Parse(os.Args[3:])
+	// 支持把选项写在服务名之后
+	service := flags.Arg(0)
+	if flags.NArg() > 1 {
+		_ = flags.Parse(flags.Args()[1:])
+	}
+	if sub != "list" && service == "" {
+		fmt.Printf("[go-boot] error: service name is required: go-boot registry %s <service>\n", sub)
+		os.Exit(1)
+	}
+
+	reg, err := openRegistry(*configFile)
+	if err != nil {
+		fmt.Printf("[go-boot] error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch sub {
+	case "list":
+		err = registryList(reg, *jsonOutput)
+	case "get":
+		err = registryGet(reg, service, *jsonOutput)
+	case "watch":
+		err = registryWatch(reg, service, *jsonOutput)
+	}
+	// os.Exit 不会执行 defer，退出前关闭注册中心连接（停止 nacos 轮询、释放 etcd 连接等）
+	_ = reg.Close()
+	if err != nil {
+		fmt.Printf("[go-boot] error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// openRegistry 根据配置文件中的 app.discovery 连接注册中心
+func openRegistry(configFile string) (*registry.Registry, error) {
+	if configFile == "" {
+		configFile = config.FindConfigFile("")
+	}
+	if configFile == "" {
+		return nil, fmt.Errorf("config file not found, use -config to specify one")
+	}
+	if _, err := os.Stat(configFile); err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("load %s failed: %w", configFile, err)
+	}
+	if cfg.App.Discovery == nil {
+		return nil, fmt.Errorf("app.discovery is not configured in %s", configFile)
+	}
+
+	reg, err := registry.New(cfg.App.Discovery)
+	if err != nil {
+		return nil, err
+	}
+	if reg == nil {
+		return nil, fmt.Errorf("app.discovery.endpoints is not configured in %s", configFile)
+	}
+	return reg, nil
+}
+
+// registryList 列出注册中心中的服务
+func registryList(reg *registry.Registry, jsonOutput bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), registryTimeout)
+	defer cancel()
+	services, err := reg.Services(ctx)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printJSON(services)
+	}
+	for _, name := range services {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+// registryGet 输出服务的所有实例
+func registryGet(reg *registry.Registry, service string, jsonOutput bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), registryTimeout)
+	defer cancel()
+	instances, err := reg.GetService(ctx, service)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printJSON(instances)
+	}
+	printInstances(instances)
+	return nil
+}
+
+// registryWatch 持续输出服务实例的变化，直到收到中断信号
+func registryWatch(reg *registry.Registry, service string, jsonOutput bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher, err := reg.Watch(ctx, service)
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	fmt.Printf("[go-boot] watching %s, press Ctrl+C to stop\n", service)
+	for {
+		instances, err := watcher.Next()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			if err := printJSON(instances); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Printf("\n[%s] %d instance(s)\n", time.Now().Format(time.RFC3339), len(instances))
+		printInstances(instances)
+	}
+}
+
+// printInstances 以表格输出服务实例
+func printInstances(instances []*kratosRegistry.ServiceInstance) {
+	if len(instances) == 0 {
+		fmt.Println("no instances")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tVERSION\tENDPOINTS\tMETADATA")
+	for _, ins := range instances {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", ins.ID, ins.Name, ins.Version, strings.Join(ins.Endpoints, ","), formatMetadata(ins.Metadata))
+	}
+	_ = w.Flush()
+}
+
+// formatMetadata 按 key 排序输出 k=v 列表
+func formatMetadata(md map[string]string) string {
+	pairs := make([]string, 0, len(md))
+	for k, v := range md {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printRegistryHelp() {
+	fmt.Print(`Usage:
+  go-boot registry list                 list services in the registry
+  go-boot registry get <service>        show instances of a service
+  go-boot registry watch <service>      watch instance changes of a service
+
+Flags:
+  -config string   config file path (default ./config.yaml or ./configs/config.yaml)
+  -json            print as JSON
+`)
+}
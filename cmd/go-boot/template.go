@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"golang.org/x/mod/module"
+)
+
+// goDirective 新项目 go.mod 的 go 版本，与 go-boot 保持一致
+const goDirective = "1.25.5"
+
+// 可选的注册中心与功能
+var (
+	registryTypes = []string{"etcd", "consul", "nacos", "kubernetes", "static"}
+	featureNames  = []string{"metrics", "tracing", "i18n"}
+)
+
+// project 项目模板数据，模板中通过 {{.Module}}、{{.Service}} 等引用
+type project struct {
+	Module        string   // 模块路径
+	Service       string   // 服务名（传给 wireApp，用于服务注册与日志）
+	GoVersion     string   // go.mod 的 go 版本
+	GoBootVersion string   // go-boot 版本
+	HTTP          bool     // 是否启动 HTTP 服务
+	GRPC          bool     // 是否启动 gRPC 服务
+	Registry      string   // 注册中心类型，为空时不启用
+	Features      []string // 启用的功能：metrics, tracing, i18n
+}
+
+// newProject 创建默认的项目模板数据：同时启动 HTTP 与 gRPC，不启用注册中心与可选功能
+func newProject(module, service string) *project {
+	version, _ := goBootVersion()
+	return &project{
+		Module:        module,
+		Service:       service,
+		GoVersion:     goDirective,
+		GoBootVersion: version,
+		HTTP:          true,
+		GRPC:          true,
+	}
+}
+
+// HasFeature 是否启用了指定功能，模板中使用 {{if .HasFeature "metrics"}}
+func (p *project) HasFeature(name string) bool {
+	return slices.Contains(p.Features, name)
+}
+
+// RegistryEndpoint 注册中心的默认地址，kubernetes 与 static 不需要地址
+func (p *project) RegistryEndpoint() string {
+	switch p.Registry {
+	case "etcd":
+		return "127.0.0.1:2379"
+	case "consul":
+		return "127.0.0.1:8500"
+	case "nacos":
+		return "127.0.0.1:8848"
+	}
+	return ""
+}
+
+// validate 校验项目参数
+func (p *project) validate() error {
+	if err := checkModulePath(p.Module); err != nil {
+		return err
+	}
+	if !p.HTTP && !p.GRPC {
+		return fmt.Errorf("at least one of -http and -grpc must be enabled")
+	}
+	if p.Registry != "" && !slices.Contains(registryTypes, p.Registry) {
+		return fmt.Errorf("unsupported registry %q, supported: %s", p.Registry, strings.Join(registryTypes, ", "))
+	}
+	for _, f := range p.Features {
+		if !slices.Contains(featureNames, f) {
+			return fmt.Errorf("unsupported feature %q, supported: %s", f, strings.Join(featureNames, ", "))
+		}
+	}
+	return nil
+}
+
+// checkModulePath 校验模块路径
+// 与 go mod init 一致，允许第一段不含域名的本地模块路径（如 order-service）
+func checkModulePath(path string) error {
+	err := module.CheckPath(path)
+	if err == nil {
+		return nil
+	}
+	if first, _, _ := strings.Cut(path, "/"); !strings.Contains(first, ".") && module.CheckImportPath(path) == nil {
+		return nil
+	}
+	return fmt.Errorf("invalid module path %q, use -module to specify one: %w", path, err)
+}
+
+// templateConditions 按条件生成的文件（目标路径），条件不满足时不生成，自定义模板覆盖后同样生效
+var templateConditions = map[string]func(p *project) bool{
+	"internal/server/http.go":        func(p *project) bool { return p.HTTP },
+	"internal/server/grpc.go":        func(p *project) bool { return p.GRPC },
+	"api/network/v1/ping_http.pb.go": func(p *project) bool { return p.HTTP },
+	"i18n/zh-CN.yaml":                func(p *project) bool { return p.HasFeature("i18n") },
+	"i18n/en.yaml":                   func(p *project) bool { return p.HasFeature("i18n") },
+}
+
+// included 目标文件是否需要生成
+func (p *project) included(target string) bool {
+	cond, ok := templateConditions[target]
+	return !ok || cond(p)
+}
+
+// renderTemplate 使用 text/template 渲染模板，Go 文件渲染后再格式化，渲染结果为空时返回空内容
+func renderTemplate(name, content string, p *project) ([]byte, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse template %s failed: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, p); err != nil {
+		return nil, fmt.Errorf("render template %s failed: %w", name, err)
+	}
+	if len(bytes.TrimSpace(buf.Bytes())) == 0 {
+		return nil, nil
+	}
+	if !strings.HasSuffix(name, ".go.tpl") {
+		return buf.Bytes(), nil
+	}
+	// 条件块会留下多余的空行，统一交给 gofmt 整理
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format %s failed: %w", name, err)
+	}
+	return src, nil
+}
+
+// templateFile 模板文件所在的模板层与路径
+type templateFile struct {
+	fsys fs.FS
+	path string
+}
+
+// collectTemplateFiles 按目标路径（去掉 .tpl 后缀）汇总各模板层的文件，后面的模板层优先
+func collectTemplateFiles(layers []fs.FS) (map[string]templateFile, error) {
+	files := make(map[string]templateFile)
+	for _, fsys := range layers {
+		err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if d.Name() == ".git" {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			files[strings.TrimSuffix(path, ".tpl")] = templateFile{fsys: fsys, path: path}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// templateLayers 返回按顺序叠加的模板层：内置模板、~/.go-boot/templates 中配置的模板、命令行指定的模板
+// 返回的 cleanup 删除克隆 git 仓库使用的临时目录
+func templateLayers(sources []string) ([]fs.FS, func(), error) {
+	builtin, err := fs.Sub(templatesFS, "templates")
+	if err != nil {
+		return nil, nil, err
+	}
+	layers := []fs.FS{builtin}
+
+	var tmpDirs []string
+	cleanup := func() {
+		for _, dir := range tmpDirs {
+			_ = os.RemoveAll(dir)
+		}
+	}
+
+	userSources, err := userTemplateSources()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, src := range append(userSources, sources...) {
+		dir := src
+		if isGitURL(src) {
+			if dir, err = cloneTemplate(src); err != nil {
+				cleanup()
+				return nil, nil, err
+			}
+			tmpDirs = append(tmpDirs, dir)
+		} else if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			cleanup()
+			return nil, nil, fmt.Errorf("template directory %s not found", src)
+		}
+		fmt.Printf("[go-boot] using templates from %s\n", src)
+		layers = append(layers, os.DirFS(dir))
+	}
+	return layers, cleanup, nil
+}
+
+// userTemplateSources 读取 ~/.go-boot/templates
+// 为目录时直接作为模板层；为文件时每行一个模板目录或 git 地址，# 开头为注释，相对路径相对于 ~/.go-boot
+func userTemplateSources() ([]string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, nil
+	}
+	path := filepath.Join(home, ".go-boot", "templates")
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil
+	}
+	if info.IsDir() {
+		return []string{path}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sources []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !isGitURL(line) && !filepath.IsAbs(line) {
+			line = filepath.Join(filepath.Dir(path), line)
+		}
+		sources = append(sources, line)
+	}
+	return sources, nil
+}
+
+// isGitURL 是否为 git 仓库地址（https://、ssh://、file:// 或 git@host:path），可以用 #ref 指定分支或标签
+// 其他形式按本地目录处理，避免把以 - 开头等特殊的字符串交给 git
+func isGitURL(src string) bool {
+	for _, prefix := range []string{"https://", "ssh://", "file://", "git@"} {
+		if strings.HasPrefix(src, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneTemplate 将模板仓库浅克隆到临时目录
+func cloneTemplate(src string) (string, error) {
+	url, ref, _ := strings.Cut(src, "#")
+	if strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("invalid template ref %q in %s", ref, src)
+	}
+	dir, err := os.MkdirTemp("", "go-boot-template-")
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Printf("[go-boot] cloning templates from %s...\n", src)
+	cmd := exec.Command("git", cloneArgs(url, ref, dir)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", fmt.Errorf("clone template %s failed: %w", src, err)
+	}
+	return dir, nil
+}
+
+// cloneArgs git clone 的参数，-- 之后的地址与目录不会被当作选项解析
+func cloneArgs(url, ref, dir string) []string {
+	args := []string{"-c", "advice.detachedHead=false", "clone", "--depth", "1"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	return append(args, "--", url, dir)
+}
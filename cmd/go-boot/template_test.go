@@ -0,0 +1,312 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestProjectValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(p *project)
+		wantErr bool
+	}{
+		{name: "default", modify: func(p *project) {}},
+		{name: "local module", modify: func(p *project) { p.Module = "order-service" }},
+		{name: "nested local module", modify: func(p *project) { p.Module = "acme/order-service" }},
+		{name: "dot directory", modify: func(p *project) { p.Module = "." }, wantErr: true},
+		{name: "space", modify: func(p *project) { p.Module = "order service" }, wantErr: true},
+		{name: "leading dash", modify: func(p *project) { p.Module = "-order" }, wantErr: true},
+		{name: "invalid domain module", modify: func(p *project) { p.Module = "example.com/Order Service" }, wantErr: true},
+		{name: "no server", modify: func(p *project) { p.HTTP, p.GRPC = false, false }, wantErr: true},
+		{name: "registry", modify: func(p *project) { p.Registry = "etcd" }},
+		{name: "unknown registry", modify: func(p *project) { p.Registry = "zookeeper" }, wantErr: true},
+		{name: "features", modify: func(p *project) { p.Features = []string{"metrics", "i18n"} }},
+		{name: "unknown feature", modify: func(p *project) { p.Features = []string{"cache"} }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newProject("github.com/acme/order-service", "order-service")
+			tt.modify(p)
+			if err := p.validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "plain file",
+			file:    "configs/config.yaml.tpl",
+			content: "name: {{.Service}}\n{{if .HasFeature \"metrics\"}}metrics: true\n{{end}}",
+			want:    "name: demo\n",
+		},
+		{
+			name:    "go file is formatted",
+			file:    "main.go.tpl",
+			content: "package main\n\n\n{{if .HTTP}}\n\n\nvar   http = true\n{{end}}{{if .Registry}}var registry = true{{end}}\n",
+			want:    "package main\n\nvar http = true\n",
+		},
+		{
+			name:    "empty output",
+			file:    "internal/server/grpc.go.tpl",
+			content: "{{if .Registry}}package server{{end}}\n",
+			want:    "",
+		},
+		{name: "unknown field", file: "a.tpl", content: "{{.Unknown}}", wantErr: true},
+		{name: "invalid go", file: "a.go.tpl", content: "package main\nfunc {", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderTemplate(tt.file, tt.content, newProject("example.com/demo", "demo"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("renderTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyTemplateFiles(t *testing.T) {
+	builtin := fstest.MapFS{
+		"go.mod.tpl":                   {Data: []byte("module {{.Module}}\n")},
+		"README.md":                    {Data: []byte("{{.Service}}\n")},
+		"internal/server/http.go.tpl":  {Data: []byte("package server\n")},
+		"internal/server/grpc.go.tpl":  {Data: []byte("package server\n")},
+		"internal/data/data.go.tpl":    {Data: []byte("package data\n")},
+		"configs/config.yaml.tpl":      {Data: []byte("name: {{.Service}}\n")},
+		"i18n/zh-CN.yaml":              {Data: []byte("hello: 你好\n")},
+		"internal/service/ping.go.tpl": {Data: []byte("package service\n")},
+	}
+	overlay := fstest.MapFS{
+		"configs/config.yaml.tpl":    {Data: []byte("name: {{.Service}}\nteam: acme\n")},
+		"internal/data/data.go.tpl":  {Data: []byte("{{/* 去掉内置文件 */}}\n")},
+		"internal/server/http.go":    {Data: []byte("package server // custom\n")},
+		"internal/biz/biz.go.tpl":    {Data: []byte("package biz\n")},
+		".git/config":                {Data: []byte("[core]\n")},
+		"internal/service/ping.go":   {Data: []byte("package service // plain\n")},
+		"internal/service/extra.txt": {Data: []byte("{{.Service}}\n")},
+	}
+
+	tests := []struct {
+		name   string
+		layers []fs.FS
+		modify func(p *project)
+		want   map[string]string // 目标文件 -> 内容，空字符串表示不生成
+	}{
+		{
+			name:   "builtin",
+			layers: []fs.FS{builtin},
+			want: map[string]string{
+				"go.mod":                  "module example.com/demo\n",
+				"README.md":               "{{.Service}}\n",
+				"internal/server/http.go": "package server\n",
+				"internal/server/grpc.go": "package server\n",
+				"internal/data/data.go":   "package data\n",
+				"i18n/zh-CN.yaml":         "",
+			},
+		},
+		{
+			name:   "conditions",
+			layers: []fs.FS{builtin},
+			modify: func(p *project) {
+				p.HTTP = false
+				p.Features = []string{"i18n"}
+			},
+			want: map[string]string{
+				"internal/server/http.go": "",
+				"internal/server/grpc.go": "package server\n",
+				"i18n/zh-CN.yaml":         "hello: 你好\n",
+			},
+		},
+		{
+			name:   "overlay",
+			layers: []fs.FS{builtin, overlay},
+			want: map[string]string{
+				"configs/config.yaml":        "name: demo\nteam: acme\n",
+				"internal/data/data.go":      "",
+				"internal/server/http.go":    "package server // custom\n",
+				"internal/biz/biz.go":        "package biz\n",
+				"internal/service/ping.go":   "package service // plain\n",
+				"internal/service/extra.txt": "{{.Service}}\n",
+				".git/config":                "",
+			},
+		},
+		{
+			name:   "overlay respects conditions",
+			layers: []fs.FS{builtin, overlay},
+			modify: func(p *project) { p.HTTP = false },
+			want:   map[string]string{"internal/server/http.go": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			p := newProject("example.com/demo", "demo")
+			if tt.modify != nil {
+				tt.modify(p)
+			}
+			if err := copyTemplateFiles(tt.layers, dir, p); err != nil {
+				t.Fatal(err)
+			}
+			for target, want := range tt.want {
+				got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(target)))
+				if want == "" {
+					if err == nil {
+						t.Errorf("%s should not be generated", target)
+					}
+					continue
+				}
+				if err != nil {
+					t.Errorf("%s: %v", target, err)
+					continue
+				}
+				if string(got) != want {
+					t.Errorf("%s = %q, want %q", target, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestBuiltinTemplates 按不同的项目参数渲染内置模板，确保模板都能渲染且 Go 文件可以格式化
+func TestBuiltinTemplates(t *testing.T) {
+	builtin, err := fs.Sub(templatesFS, "templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		modify     func(p *project)
+		want, skip []string
+	}{
+		{
+			name: "default",
+			want: []string{"main.go", "wire_gen.go", "go.mod", "internal/server/http.go", "internal/server/grpc.go", "api/network/v1/ping_http.pb.go"},
+			skip: []string{"i18n/zh-CN.yaml", "i18n/en.yaml"},
+		},
+		{
+			name:   "http only",
+			modify: func(p *project) { p.GRPC = false },
+			want:   []string{"internal/server/http.go", "api/network/v1/ping_http.pb.go"},
+			skip:   []string{"internal/server/grpc.go"},
+		},
+		{
+			name:   "grpc only",
+			modify: func(p *project) { p.HTTP = false },
+			want:   []string{"internal/server/grpc.go"},
+			skip:   []string{"internal/server/http.go", "api/network/v1/ping_http.pb.go"},
+		},
+		{
+			name: "registry and features",
+			modify: func(p *project) {
+				p.Registry = "nacos"
+				p.Features = featureNames
+			},
+			want: []string{"i18n/zh-CN.yaml", "i18n/en.yaml"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			p := newProject("example.com/demo", "demo")
+			if tt.modify != nil {
+				tt.modify(p)
+			}
+			if err := copyTemplateFiles([]fs.FS{builtin}, dir, p); err != nil {
+				t.Fatal(err)
+			}
+			for _, target := range tt.want {
+				if _, err := os.Stat(filepath.Join(dir, target)); err != nil {
+					t.Errorf("%s should be generated: %v", target, err)
+				}
+			}
+			for _, target := range tt.skip {
+				if _, err := os.Stat(filepath.Join(dir, target)); err == nil {
+					t.Errorf("%s should not be generated", target)
+				}
+			}
+			err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				if strings.HasSuffix(path, ".tpl") {
+					t.Errorf("%s should be rendered without the .tpl suffix", path)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestIsGitURL(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"https://git.example.com/platform/templates.git#v1.2.0", true},
+		{"ssh://git@git.example.com/platform/templates.git", true},
+		{"git@git.example.com:platform/templates.git", true},
+		{"file:///srv/templates.git", true},
+		{"http://git.example.com/platform/templates.git", false},
+		{"git://git.example.com/platform/templates.git", false},
+		{"templates.git", false},
+		{"--upload-pack=touch /tmp/x", false},
+		{"./templates", false},
+		{"/srv/templates", false},
+	}
+	for _, tt := range tests {
+		if got := isGitURL(tt.src); got != tt.want {
+			t.Errorf("isGitURL(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestCloneArgs(t *testing.T) {
+	tests := []struct {
+		name, url, ref string
+		want           string
+	}{
+		{
+			name: "default branch",
+			url:  "https://git.example.com/templates.git",
+			want: "-c advice.detachedHead=false clone --depth 1 -- https://git.example.com/templates.git /tmp/dir",
+		},
+		{
+			name: "ref",
+			url:  "https://git.example.com/templates.git",
+			ref:  "v1.2.0",
+			want: "-c advice.detachedHead=false clone --depth 1 --branch v1.2.0 -- https://git.example.com/templates.git /tmp/dir",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(cloneArgs(tt.url, tt.ref, "/tmp/dir"), " "); got != tt.want {
+				t.Fatalf("cloneArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloneTemplateRejectsOptionRef(t *testing.T) {
+	if _, err := cloneTemplate("https://git.example.com/templates.git#--upload-pack=touch"); err == nil {
+		t.Fatal("ref starting with - should be rejected")
+	}
+}
@@ -4,7 +4,7 @@ package api.network.v1;
 
 import "google/api/annotations.proto";
 
-option go_package = "%s/api/network/v1";
+option go_package = "{{.Module}}/api/network/v1";
 
 // 提供 ping 接口
 service Ping {
@@ -0,0 +1,127 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: api/network/v1/ping.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Ping_Ping_FullMethodName = "/api.network.v1.Ping/Ping"
+)
+
+// PingClient is the client API for Ping service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// 提供 ping 接口
+type PingClient interface {
+	// Ping 健康检查接口
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingReply, error)
+}
+
+type pingClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPingClient(cc grpc.ClientConnInterface) PingClient {
+	return &pingClient{cc}
+}
+
+func (c *pingClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PingReply)
+	err := c.cc.Invoke(ctx, Ping_Ping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PingServer is the server API for Ping service.
+// All implementations must embed UnimplementedPingServer
+// for forward compatibility.
+//
+// 提供 ping 接口
+type PingServer interface {
+	// Ping 健康检查接口
+	Ping(context.Context, *PingRequest) (*PingReply, error)
+	mustEmbedUnimplementedPingServer()
+}
+
+// UnimplementedPingServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPingServer struct{}
+
+func (UnimplementedPingServer) Ping(context.Context, *PingRequest) (*PingReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (UnimplementedPingServer) mustEmbedUnimplementedPingServer() {}
+func (UnimplementedPingServer) testEmbeddedByValue()              {}
+
+// UnsafePingServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PingServer will
+// result in compilation errors.
+type UnsafePingServer interface {
+	mustEmbedUnimplementedPingServer()
+}
+
+func RegisterPingServer(s grpc.ServiceRegistrar, srv PingServer) {
+	// If the following call pancis, it indicates UnimplementedPingServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Ping_ServiceDesc, srv)
+}
+
+func _Ping_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PingServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ping_Ping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PingServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Ping_ServiceDesc is the grpc.ServiceDesc for Ping service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Ping_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.network.v1.Ping",
+	HandlerType: (*PingServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ping",
+			Handler:    _Ping_Ping_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/network/v1/ping.proto",
+}
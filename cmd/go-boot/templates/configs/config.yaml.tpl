@@ -3,31 +3,88 @@
 
 server:
   grpc:
-    addr: ":9000"      # gRPC 服务地址，留空则不启动 gRPC 服务
+    addr: {{if .GRPC}}":9000"{{else}}""     {{end}}      # gRPC 服务地址，留空则不启动 gRPC 服务
     timeout: "30s"     # gRPC 请求超时时间（可选，如 "30s", "1m"）
   http:
-    addr: ":8000"      # HTTP 服务地址，留空则不启动 HTTP 服务
+    addr: {{if .HTTP}}":8000"{{else}}""     {{end}}      # HTTP 服务地址，留空则不启动 HTTP 服务
     timeout: "30s"     # HTTP 请求超时时间（可选，如 "30s", "1m"）
+  admin:
+    addr: "127.0.0.1:8090" # 管理服务地址（pprof、配置、健康检查、指标），接口无鉴权，默认只监听本机，留空则不启动
 
 middleware:
-  enableMetrics: false  # 是否启用监控指标（基于 OpenTelemetry）
-  enableTracing: false  # 是否启用链路追踪（基于 OpenTelemetry）
+  enableMetrics: {{printf "%-5t" (.HasFeature "metrics")}}  # 是否启用监控指标（基于 OpenTelemetry）
+  enableTracing: {{printf "%-5t" (.HasFeature "tracing")}}  # 是否启用链路追踪（基于 OpenTelemetry）
 
 app:
   version: "v1.0.0"     # 应用版本（可选，默认 v1.0.0）
   stopTimeout: "10s"    # 优雅关闭超时时间（可选，默认 10s）
+  preStopDelay: "5s"    # 预停止等待时间：注销服务、就绪检查失败后等待多久再停止服务器（可选，默认不等待）
   # 服务注册与发现配置（可选）
   # discovery:
-  #   type: "etcd"        # 注册中心类型：etcd, consul（nacos 等后续支持）
+  #   type: "etcd"        # 注册中心类型：etcd, consul, nacos, kubernetes（只做服务发现）, static / file（本地开发）
   #   register: true      # 是否开启服务注册（默认 false）
   #   endpoints:          # 注册中心地址列表（配置后自动连接 Discovery，供客户端服务发现使用）
   #     - "127.0.0.1:2379"  # etcd 示例，consul 通常使用 "127.0.0.1:8500"
   #   timeout: "5s"       # 连接超时时间（可选）
+  #   registration:       # 注册重试与保活（可选）
+  #     mode: "retry"     # retry（重试失败后启动失败，默认）, failfast, async（先提供服务，后台注册）
+  #     checkInterval: "30s" # 实例丢失（如租约过期）时自动重新注册的检查间隔
+  #   registries:         # 同时使用多个注册中心（如迁移期间同时注册到 consul 与 etcd），配置后忽略上面的注册中心配置
+  #     - type: "consul"
+  #       register: true
+  #       endpoints: ["127.0.0.1:8500"]
+  #   namespace: ""       # nacos 命名空间 ID（可选）
+  #   group: ""           # nacos 分组（默认 DEFAULT_GROUP）
+  #   username: ""        # nacos 鉴权用户名（可选）
+  #   password: ""
+{{- if eq .Registry "kubernetes"}}
+  discovery:
+    type: "kubernetes"  # 由 Service 与就绪探针完成注册，只做服务发现
+{{- else if eq .Registry "static"}}
+  discovery:
+    type: "static"
+    services: {}        # 服务名到地址列表的映射，如 service-order: ["grpc://127.0.0.1:9000"]
+{{- else if .Registry}}
+  discovery:
+    type: "{{.Registry}}"
+    register: true
+    endpoints:
+      - "{{.RegistryEndpoint}}"
+{{- end}}
   # 服务元数据（可选，用于服务注册时的标签）
   metadata:
     env: "dev"
     zone: "zone-a"
 
+# HTTP 响应配置（可选）
+response:
+  errorMode: "envelope"  # 错误响应模式：envelope（统一返回 200）, status（HTTP 状态码与错误码一致）, problem（RFC 7807）
+  protojson:
+    useJSONNames: false     # proto 消息使用 lowerCamelCase 字段名（默认使用 proto 字段名）
+    useEnumNumbers: false   # 枚举输出数字（默认输出枚举名）
+    emitUnpopulated: false  # 输出零值字段（默认省略）
+  # codecs: ["xml", "yaml"] # 额外启用的编码，客户端通过 Accept 请求头选择
+  # rawOperations: []      # 不包装统一结构的 operation（如 Webhook）
+  # rawPaths: []           # 不包装统一结构的路径前缀（如 /oauth/）
+
+# 错误消息国际化（可选）
+{{- $i18n := "# "}}{{if .HasFeature "i18n"}}{{$i18n = ""}}{{end}}
+{{$i18n}}i18n:
+{{$i18n}}  dir: "i18n"             # 消息文件目录，文件名为语言标签（zh-CN.yaml、en.yaml），内容为 reason 到消息模板的映射
+{{$i18n}}  defaultLanguage: "zh-CN" # 客户端未指定或不支持时使用的语言
+
+# 客户端配置（可选，用于 bootstrap.NewGRPCClient / NewHTTPClient 调用其他服务）
+# client:
+#   timeout: "2s"           # 默认请求超时
+#   balancer: "wrr"         # 负载均衡算法：rr, wrr, random, p2c, hash
+#   zoneAffinity: false     # 优先调用相同可用区（app.metadata.zone）的实例
+#   targets:
+#     service-order:
+#       timeout: "5s"       # 按目标服务覆盖超时
+#       endpoint: ""        # 直连地址（可选，配置后不走服务发现）
+#       version: ""         # 只调用该版本的实例（灰度）
+#       envelope: true      # HTTP 客户端解码 go-boot 统一响应结构
+
 log:
   output: "logs/app.log"  # 日志输出位置：stdout, stderr, 或文件路径（默认 logs/app.log）
   level: "info"           # 日志级别：debug, info, warn, error（默认 info）
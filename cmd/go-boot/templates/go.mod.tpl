@@ -0,0 +1,5 @@
+module {{.Module}}
+
+go {{.GoVersion}}
+
+require github.com/addls/go-boot {{.GoBootVersion}}
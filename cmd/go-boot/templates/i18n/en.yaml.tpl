@@ -0,0 +1,2 @@
+# reason to message template, template params come from the error metadata
+USER_NOT_FOUND: "user {{`{{.id}}`}} not found"
@@ -0,0 +1,2 @@
+# reason 到错误消息模板的映射，模板参数来自错误的 metadata
+USER_NOT_FOUND: "用户 {{`{{.id}}`}} 不存在"
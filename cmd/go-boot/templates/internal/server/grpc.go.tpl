@@ -1,12 +1,15 @@
 package server
 
 import (
-	v1 "%s/api/network/v1"
-	"%s/internal/service"
+	v1 "{{.Module}}/api/network/v1"
+	"{{.Module}}/internal/service"
 
 	"github.com/go-kratos/kratos/v2/transport/grpc"
 )
 
-func RegisterGRPCServer(grpcSrv *grpc.Server) {
-	v1.RegisterPingServer(grpcSrv.Server, service.NewPingService())
+// NewGRPCRegister 注册 gRPC 服务
+func NewGRPCRegister(ping *service.PingService) func(*grpc.Server) {
+	return func(grpcSrv *grpc.Server) {
+		v1.RegisterPingServer(grpcSrv.Server, ping)
+	}
 }
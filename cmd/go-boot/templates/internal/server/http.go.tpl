@@ -1,12 +1,15 @@
 package server
 
 import (
-	v1 "%s/api/network/v1"
-	"%s/internal/service"
+	v1 "{{.Module}}/api/network/v1"
+	"{{.Module}}/internal/service"
 
 	"github.com/go-kratos/kratos/v2/transport/http"
 )
 
-func RegisterHTTPServer(httpSrv *http.Server) {
-	v1.RegisterPingHTTPServer(httpSrv, service.NewPingService())
+// NewHTTPRegister 注册 HTTP 路由
+func NewHTTPRegister(ping *service.PingService) func(*http.Server) {
+	return func(httpSrv *http.Server) {
+		v1.RegisterPingHTTPServer(httpSrv, ping)
+	}
 }
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"github.com/addls/go-boot/bootstrap"
+{{- if .GRPC}}
+	"github.com/go-kratos/kratos/v2/transport/grpc"
+{{- end}}
+{{- if .HTTP}}
+	"github.com/go-kratos/kratos/v2/transport/http"
+{{- end}}
+	"github.com/google/wire"
+)
+
+// ProviderSet server 层 Provider 集合
+var ProviderSet = wire.NewSet({{if .HTTP}}NewHTTPRegister, {{end}}{{if .GRPC}}NewGRPCRegister, {{end}}NewRegisters)
+
+// NewRegisters 汇总 HTTP/gRPC 服务注册，交给底座在服务器创建后执行
+func NewRegisters({{if .HTTP}}httpRegister func(*http.Server){{end}}{{if and .HTTP .GRPC}}, {{end}}{{if .GRPC}}grpcRegister func(*grpc.Server){{end}}) bootstrap.Registers {
+	return bootstrap.Registers{
+{{- if .HTTP}}
+		HTTP: []func(*http.Server){httpRegister},
+{{- end}}
+{{- if .GRPC}}
+		GRPC: []func(*grpc.Server){grpcRegister},
+{{- end}}
+	}
+}
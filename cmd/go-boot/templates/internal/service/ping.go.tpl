@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	pb "{{.Module}}/api/network/v1"
+)
+
+// PingService 实现 api/network/v1/ping.proto 中定义的 Ping 服务
+type PingService struct {
+	pb.UnimplementedPingServer
+}
+
+// NewPingService 创建 PingService
+func NewPingService() *PingService {
+	return &PingService{}
+}
+
+// Ping 健康检查接口
+func (s *PingService) Ping(ctx context.Context, req *pb.PingRequest) (*pb.PingReply, error) {
+	return &pb.PingReply{Message: "pong"}, nil
+}
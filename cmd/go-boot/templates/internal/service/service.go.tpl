@@ -0,0 +1,7 @@
+package service
+
+import "github.com/google/wire"
+
+// ProviderSet service 层 Provider 集合
+// 新增 service 后在这里添加对应的构造函数，并执行 wire 重新生成 wire_gen.go
+var ProviderSet = wire.NewSet(NewPingService)
@@ -1,13 +1,22 @@
 package main
 
 import (
-	"%s/internal/server"
-	"github.com/addls/go-boot/bootstrap"
+	"fmt"
+	"os"
 )
 
 func main() {
-	bootstrap.Run("%s",
-		bootstrap.WithHTTPRegister(server.RegisterHTTPServer),
-		bootstrap.WithGRPCRegister(server.RegisterGRPCServer),
-	)
+	app, cleanup, err := wireApp("{{.Service}}")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "init app failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 启动失败时 cleanup 关闭已启动的组件，需要在 os.Exit 之前执行
+	err = app.Run()
+	cleanup()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "run app failed: %v\n", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,19 @@
+//go:build wireinject
+// +build wireinject
+
+package main
+
+import (
+	"{{.Module}}/internal/server"
+	"{{.Module}}/internal/service"
+
+	"github.com/addls/go-boot/bootstrap"
+	"github.com/google/wire"
+)
+
+// wireApp 组合底座与业务的 Provider 集合
+// 业务 Provider 可以直接依赖 *config.Config、log.Logger、registry.Discovery、*bootstrap.Lifecycle、*bootstrap.ClientFactory 等底座组件
+// 修改后执行 wire（go run github.com/google/wire/cmd/wire）重新生成 wire_gen.go
+func wireApp(name string, opts ...bootstrap.Option) (*bootstrap.App, func(), error) {
+	panic(wire.Build(bootstrap.ProviderSet, service.ProviderSet, server.ProviderSet))
+}
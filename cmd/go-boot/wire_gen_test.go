@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestWireGenTemplate 按每种模板组合生成项目并执行 wire，确保 wire_gen.go.tpl 与 wire 的输出一致
+// 修改 bootstrap.ProviderSet 或业务模板的 Provider 后，如果该测试失败，按输出的差异更新 wire_gen.go.tpl
+func TestWireGenTemplate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go mod tidy and wire")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	builtin, err := fs.Sub(templatesFS, "templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		http, grpc bool
+	}{
+		{"http+grpc", true, true},
+		{"http", true, false},
+		{"grpc", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			p := newProject("example.com/demo", "demo")
+			p.HTTP, p.GRPC = tt.http, tt.grpc
+			if err := copyTemplateFiles([]fs.FS{builtin}, dir, p); err != nil {
+				t.Fatal(err)
+			}
+			want, err := os.ReadFile(filepath.Join(dir, "wire_gen.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 依赖当前仓库的 go-boot，而不是已发布的版本
+			run(t, dir, "go", "mod", "edit", "-replace", "github.com/addls/go-boot="+root)
+			run(t, dir, "go", "mod", "tidy")
+			run(t, dir, "go", "run", "-mod=mod", "github.com/google/wire/cmd/wire", "gen", ".")
+
+			got, err := os.ReadFile(filepath.Join(dir, "wire_gen.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("wire_gen.go.tpl is out of date, wire generated:\n%s", got)
+			}
+		})
+	}
+}
+
+func run(t *testing.T, dir, name string, args ...string) {
+	t.Helper()
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%s %v: %v\n%s", name, args, err, out)
+	}
+}
@@ -17,6 +17,17 @@ const (
 	SuccessMessage = "success" // 成功响应消息
 )
 
+// 分页默认配置
+const (
+	DefaultPageSize = 20  // 默认每页条数
+	MaxPageSize     = 100 // 每页最大条数
+)
+
+// 请求头
+const (
+	HeaderRequestID = "X-Request-Id" // 请求 ID
+)
+
 // HTTP 状态码
 const (
 	HTTPStatusOK = 200 // 成功
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"dario.cat/mergo"
 	"github.com/addls/go-boot/common"
@@ -19,28 +23,118 @@ type Config struct {
 	Middleware Middleware `json:"middleware" yaml:"middleware"`
 	App        App        `json:"app" yaml:"app"`
 	Log        Log        `json:"log" yaml:"log"`
+	Client     Client     `json:"client" yaml:"client"`
+	Response   Response   `json:"response" yaml:"response"`
+	I18n       I18n       `json:"i18n" yaml:"i18n"`
 }
 
 // App 应用配置
 type App struct {
-	Version     string            `json:"version" yaml:"version"`         // 应用版本（可选，默认 v1.0.0）
-	StopTimeout string            `json:"stopTimeout" yaml:"stopTimeout"` // 优雅关闭超时（如 "10s", "30s"）
-	Discovery   *Discovery        `json:"discovery" yaml:"discovery"`     // 服务注册与发现配置（可选）
-	Metadata    map[string]string `json:"metadata" yaml:"metadata"`       // 服务元数据（可选）
+	Version      string            `json:"version" yaml:"version"`           // 应用版本（可选，默认 v1.0.0）
+	StopTimeout  string            `json:"stopTimeout" yaml:"stopTimeout"`   // 优雅关闭超时（如 "10s", "30s"）
+	PreStopDelay string            `json:"preStopDelay" yaml:"preStopDelay"` // 预停止等待时间：注销服务并使就绪检查失败后，等待多久再停止服务器（如 "5s"）
+	Discovery    *Discovery        `json:"discovery" yaml:"discovery"`       // 服务注册与发现配置（可选）
+	Metadata     map[string]string `json:"metadata" yaml:"metadata"`         // 服务元数据（可选）
 }
 
 // Discovery 服务注册与发现配置
 type Discovery struct {
-	Type      string   `json:"type" yaml:"type"`           // 注册中心类型：etcd, consul, nacos 等
+	Type      string   `json:"type" yaml:"type"`           // 注册中心类型：etcd, consul, nacos, kubernetes, static, file
 	Register  bool     `json:"register" yaml:"register"`   // 是否开启服务注册（默认 false）
 	Endpoints []string `json:"endpoints" yaml:"endpoints"` // 注册中心地址列表（配置后自动连接 Discovery）
 	Timeout   string   `json:"timeout" yaml:"timeout"`     // 连接超时（如 "5s"）
+
+	Registration *Registration `json:"registration" yaml:"registration"` // 服务注册重试与保活（可选）
+	Registries   []*Discovery  `json:"registries" yaml:"registries"`     // 同时使用多个注册中心（如迁移期间同时注册到 consul 与 etcd），配置后忽略本级的注册中心配置
+
+	// 连接与鉴权
+	TLS       *TLS   `json:"tls" yaml:"tls"`             // TLS 配置（etcd、consul）
+	Username  string `json:"username" yaml:"username"`   // 用户名（etcd、consul HTTP Basic、nacos）
+	Password  string `json:"password" yaml:"password"`   // 密码
+	Token     string `json:"token" yaml:"token"`         // ACL token（consul）
+	Namespace string `json:"namespace" yaml:"namespace"` // 命名空间：etcd 为 key 前缀（默认 /microservices），consul 为企业版命名空间，nacos 为命名空间 ID（默认 public），kubernetes 为默认命名空间（默认当前 Pod 所在命名空间）
+
+	// consul 配置
+	Datacenter  string       `json:"datacenter" yaml:"datacenter"`   // 数据中心（默认使用 agent 所在数据中心）
+	HealthCheck *HealthCheck `json:"healthCheck" yaml:"healthCheck"` // 健康检查（consul），ttl 同时用于 etcd 租约
+
+	// nacos 配置
+	Group        string   `json:"group" yaml:"group"`               // 分组（默认 DEFAULT_GROUP）
+	Cluster      string   `json:"cluster" yaml:"cluster"`           // 注册到的集群（默认 DEFAULT）
+	Clusters     []string `json:"clusters" yaml:"clusters"`         // 服务发现只返回这些集群的实例（默认所有集群）
+	Weight       float64  `json:"weight" yaml:"weight"`             // 实例权重（默认 100，app.metadata.weight 优先）
+	Ephemeral    *bool    `json:"ephemeral" yaml:"ephemeral"`       // 是否为临时实例（默认 true，临时实例通过心跳保活）
+	PollInterval string   `json:"pollInterval" yaml:"pollInterval"` // 监听服务实例时查询实例列表的间隔（默认 5s）
+
+	// kubernetes 配置
+	Kubeconfig string `json:"kubeconfig" yaml:"kubeconfig"` // kubeconfig 文件路径（可选，默认集群内认证，集群外使用默认 kubeconfig）
+
+	// static / file 配置
+	Services map[string][]string `json:"services" yaml:"services"` // static：服务名到地址列表的映射（如 grpc://127.0.0.1:9000）
+	File     string              `json:"file" yaml:"file"`         // file：服务地址文件路径（YAML/JSON，修改后自动生效）
+}
+
+// TLS 客户端 TLS 配置
+type TLS struct {
+	CAFile             string `json:"caFile" yaml:"caFile"`                         // CA 证书
+	CertFile           string `json:"certFile" yaml:"certFile"`                     // 客户端证书（双向认证时配置）
+	KeyFile            string `json:"keyFile" yaml:"keyFile"`                       // 客户端私钥
+	ServerName         string `json:"serverName" yaml:"serverName"`                 // 校验的服务端名称（可选）
+	InsecureSkipVerify bool   `json:"insecureSkipVerify" yaml:"insecureSkipVerify"` // 跳过服务端证书校验（仅用于测试）
+}
+
+// Config 创建 tls.Config
+func (t *TLS) Config() (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+	if t.CAFile != "" {
+		ca, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("invalid ca file: %s", t.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// HealthCheck 注册中心健康检查配置
+type HealthCheck struct {
+	Type            string `json:"type" yaml:"type"`                       // 检查方式：ttl（心跳）, tcp, http, grpc, none（默认 tcp + ttl）
+	Interval        string `json:"interval" yaml:"interval"`               // 检查间隔（默认 10s，最小 1s，ttl 心跳超时为两倍间隔）
+	DeregisterAfter string `json:"deregisterAfter" yaml:"deregisterAfter"` // 检查持续失败多久后注销实例（默认 10m）
+	HTTP            string `json:"http" yaml:"http"`                       // http 检查地址（如 http://127.0.0.1:8090/readyz）
+	GRPC            string `json:"grpc" yaml:"grpc"`                       // grpc 检查地址（如 127.0.0.1:9000，使用 gRPC 健康检查协议）
+	TTL             string `json:"ttl" yaml:"ttl"`                         // etcd 租约 TTL（默认 15s）/ consul 心跳超时（心跳间隔为其一半）
+}
+
+// Registration 服务注册重试与保活配置
+type Registration struct {
+	Mode          string `json:"mode" yaml:"mode"`                   // 启动时的注册方式：retry（重试失败后启动失败，默认）, failfast（只尝试一次）, async（先提供服务，后台重试直到成功）
+	MaxRetries    int    `json:"maxRetries" yaml:"maxRetries"`       // retry 模式的最大重试次数（默认 5）
+	Backoff       string `json:"backoff" yaml:"backoff"`             // 首次重试等待时间，之后每次翻倍（默认 1s）
+	MaxBackoff    string `json:"maxBackoff" yaml:"maxBackoff"`       // 最长重试等待时间（默认 30s）
+	CheckInterval string `json:"checkInterval" yaml:"checkInterval"` // 检查实例是否仍在注册中心的间隔，丢失时（如租约过期）自动重新注册（默认 30s，"0" 关闭）
+	Timeout       string `json:"timeout" yaml:"timeout"`             // 启动时注册（含重试）与停止时注销的总超时，超过后不再重试（默认 60s）
 }
 
 // Server 服务器配置
 type Server struct {
-	GRPC ServerConfig `json:"grpc" yaml:"grpc"`
-	HTTP ServerConfig `json:"http" yaml:"http"`
+	GRPC  ServerConfig `json:"grpc" yaml:"grpc"`
+	HTTP  ServerConfig `json:"http" yaml:"http"`
+	Admin ServerConfig `json:"admin" yaml:"admin"` // 管理端口（pprof、配置、健康检查、指标等），默认不启动，配置地址后启用
 }
 
 // ServerConfig 服务器配置项
@@ -49,6 +143,50 @@ type ServerConfig struct {
 	Timeout string `json:"timeout" yaml:"timeout"`
 }
 
+// Client 客户端配置（调用其他 go-boot 服务）
+type Client struct {
+	Timeout      string                  `json:"timeout" yaml:"timeout"`           // 默认请求超时（如 "2s"，默认使用 Kratos 默认值）
+	Balancer     string                  `json:"balancer" yaml:"balancer"`         // 默认负载均衡算法：rr, wrr（默认，按实例 metadata.weight 加权）, random, p2c（EWMA 延迟）, hash（一致性哈希）
+	HashKey      string                  `json:"hashKey" yaml:"hashKey"`           // hash 算法使用的请求元数据键（如 x-md-global-uid），请求中没有该键时随机选择
+	ZoneAffinity bool                    `json:"zoneAffinity" yaml:"zoneAffinity"` // 优先调用与本实例 app.metadata.zone 相同可用区的实例，没有时调用全部实例
+	Targets      map[string]ClientTarget `json:"targets" yaml:"targets"`           // 按目标服务名配置（可选）
+}
+
+// ClientTarget 目标服务配置
+type ClientTarget struct {
+	Timeout      string            `json:"timeout" yaml:"timeout"`           // 请求超时，覆盖 client.timeout
+	Endpoint     string            `json:"endpoint" yaml:"endpoint"`         // 直连地址（可选，配置后不走服务发现，如 "127.0.0.1:9000"）
+	Balancer     string            `json:"balancer" yaml:"balancer"`         // 负载均衡算法，覆盖 client.balancer
+	HashKey      string            `json:"hashKey" yaml:"hashKey"`           // hash 算法使用的请求元数据键，覆盖 client.hashKey
+	ZoneAffinity *bool             `json:"zoneAffinity" yaml:"zoneAffinity"` // 同可用区优先，覆盖 client.zoneAffinity
+	Version      string            `json:"version" yaml:"version"`           // 只调用该版本的实例（如灰度版本 v2.0.0）
+	Metadata     map[string]string `json:"metadata" yaml:"metadata"`         // 只调用元数据全部匹配的实例（如 env: canary）
+	Envelope     bool              `json:"envelope" yaml:"envelope"`         // HTTP 客户端解码 go-boot 统一响应结构（目标服务为 go-boot 服务时开启）
+}
+
+// Response HTTP 响应配置
+type Response struct {
+	ErrorMode string    `json:"errorMode" yaml:"errorMode"` // 错误响应模式：envelope（默认，统一返回 200）, status（HTTP 状态码与错误码一致）, problem（RFC 7807）
+	ProtoJSON ProtoJSON `json:"protojson" yaml:"protojson"` // proto 消息的 JSON 编码选项
+	Codecs    []string  `json:"codecs" yaml:"codecs"`       // 额外启用的编码（如 xml, yaml），客户端通过 Accept 请求头选择
+
+	RawOperations []string `json:"rawOperations" yaml:"rawOperations"` // 不包装统一结构的 operation（如 /api.payment.v1.Payment/Webhook）
+	RawPaths      []string `json:"rawPaths" yaml:"rawPaths"`           // 不包装统一结构的路径前缀（如 /oauth/callback）
+}
+
+// ProtoJSON proto 消息的 JSON 编码选项
+type ProtoJSON struct {
+	UseJSONNames    bool `json:"useJSONNames" yaml:"useJSONNames"`       // 使用 lowerCamelCase 字段名（默认使用 proto 字段名）
+	UseEnumNumbers  bool `json:"useEnumNumbers" yaml:"useEnumNumbers"`   // 枚举输出数字（默认输出枚举名）
+	EmitUnpopulated bool `json:"emitUnpopulated" yaml:"emitUnpopulated"` // 输出零值字段（默认省略）
+}
+
+// I18n 错误消息国际化配置
+type I18n struct {
+	Dir             string `json:"dir" yaml:"dir"`                         // 消息文件目录（如 "i18n"），文件名为语言标签（zh-CN.yaml、en.yaml），留空则不启用
+	DefaultLanguage string `json:"defaultLanguage" yaml:"defaultLanguage"` // 客户端未指定或不支持时使用的语言（如 "zh-CN"）
+}
+
 // Middleware 中间件配置
 type Middleware struct {
 	EnableMetrics bool `json:"enableMetrics" yaml:"enableMetrics"`
@@ -166,5 +304,38 @@ func LoadConfig(service, configFile string, directConfig *Config) (*Config, erro
 		}
 	}
 
+	if err := fileConfig.Validate(); err != nil {
+		return nil, err
+	}
 	return fileConfig, nil
 }
+
+// 支持的错误响应模式，与 response.ErrorMode 保持一致
+var errorModes = []string{"envelope", "status", "problem"}
+
+// registrationModes 支持的启动注册方式
+var registrationModes = []string{"retry", "failfast", "async"}
+
+// balancers 支持的负载均衡算法，与 selector 包中的常量保持一致
+var balancers = []string{"rr", "wrr", "random", "p2c", "hash"}
+
+// Validate 校验配置，取值错误时启动失败，避免拼写错误静默改变行为
+func (c *Config) Validate() error {
+	if mode := c.Response.ErrorMode; mode != "" && !slices.Contains(errorModes, mode) {
+		return fmt.Errorf("invalid response.errorMode %q, supported: %s", mode, strings.Join(errorModes, ", "))
+	}
+	if d := c.App.Discovery; d != nil && d.Registration != nil {
+		if mode := d.Registration.Mode; mode != "" && !slices.Contains(registrationModes, mode) {
+			return fmt.Errorf("invalid app.discovery.registration.mode %q, supported: %s", mode, strings.Join(registrationModes, ", "))
+		}
+	}
+	if b := c.Client.Balancer; b != "" && !slices.Contains(balancers, b) {
+		return fmt.Errorf("invalid client.balancer %q, supported: %s", b, strings.Join(balancers, ", "))
+	}
+	for name, t := range c.Client.Targets {
+		if b := t.Balancer; b != "" && !slices.Contains(balancers, b) {
+			return fmt.Errorf("invalid client.targets.%s.balancer %q, supported: %s", name, b, strings.Join(balancers, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		mode    string
+		wantErr bool
+	}{
+		{"", false},
+		{"envelope", false},
+		{"status", false},
+		{"problem", false},
+		{"Status", true},
+		{"statsu", true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Response.ErrorMode = tt.mode
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("errorMode %q: Validate() error = %v, wantErr %v", tt.mode, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateRegistrationMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		wantErr bool
+	}{
+		{"", false},
+		{"retry", false},
+		{"failfast", false},
+		{"async", false},
+		{"Retry", true},
+		{"fail-fast", true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.App.Discovery = &Discovery{Type: "etcd", Registration: &Registration{Mode: tt.mode}}
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("registration mode %q: Validate() error = %v, wantErr %v", tt.mode, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateBalancer(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  Client
+		wantErr bool
+	}{
+		{name: "default", client: Client{}},
+		{name: "supported", client: Client{Balancer: "p2c", Targets: map[string]ClientTarget{"order": {Balancer: "hash"}}}},
+		{name: "unknown default", client: Client{Balancer: "least_conn"}, wantErr: true},
+		{name: "unknown target", client: Client{Targets: map[string]ClientTarget{"order": {Balancer: "round_robin"}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Client = tt.client
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadConfigRejectsUnknownErrorMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("response:\n  errorMode: statsu\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig("test", path, nil); err == nil {
+		t.Fatal("LoadConfig() with unknown errorMode should fail")
+	}
+
+	// 直接传入的配置同样校验
+	if _, err := LoadConfig("test", "", &Config{Response: Response{ErrorMode: "bogus"}}); err == nil {
+		t.Fatal("LoadConfig() with unknown direct errorMode should fail")
+	}
+}
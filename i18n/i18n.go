@@ -0,0 +1,163 @@
+package i18n
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// HeaderAcceptLanguage 客户端期望的语言（gRPC 元数据中为小写 accept-language）
+const HeaderAcceptLanguage = "Accept-Language"
+
+// Translator 错误消息翻译器
+// 按错误 reason 查找对应语言的消息模板，模板参数取自错误元数据，例如：
+//
+//	USER_NOT_FOUND: "用户 {{.id}} 不存在"
+type Translator struct {
+	defaultLanguage string
+	messages        map[string]map[string]*template.Template // language -> reason -> template
+}
+
+// New 创建翻译器，defaultLanguage 为客户端未指定或不支持时使用的语言
+func New(defaultLanguage string) *Translator {
+	return &Translator{
+		defaultLanguage: normalize(defaultLanguage),
+		messages:        make(map[string]map[string]*template.Template),
+	}
+}
+
+// Add 添加一条消息模板
+func (t *Translator) Add(language, reason, message string) error {
+	tmpl, err := template.New(reason).Option("missingkey=zero").Parse(message)
+	if err != nil {
+		return fmt.Errorf("parse message %s/%s failed: %w", language, reason, err)
+	}
+	language = normalize(language)
+	if t.messages[language] == nil {
+		t.messages[language] = make(map[string]*template.Template)
+	}
+	t.messages[language][reason] = tmpl
+	return nil
+}
+
+// LoadDir 从目录加载消息文件
+// 文件名为语言标签（如 zh-CN.yaml、en.json），内容为 reason 到消息模板的映射
+func (t *Translator) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		// JSON 是 YAML 的子集，统一使用 YAML 解析
+		var messages map[string]string
+		if err := yaml.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("parse %s failed: %w", entry.Name(), err)
+		}
+		language := strings.TrimSuffix(entry.Name(), ext)
+		for reason, message := range messages {
+			if err := t.Add(language, reason, message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Message 按错误 reason 与 Accept-Language 查找并渲染消息，模板参数为错误元数据
+// 依次尝试客户端语言（按权重）与默认语言，返回消息与实际使用的语言；没有对应消息时 ok 为 false
+func (t *Translator) Message(reason string, params map[string]string, acceptLanguage string) (message, language string, ok bool) {
+	if reason == "" {
+		return "", "", false
+	}
+	for _, language := range append(ParseAcceptLanguage(acceptLanguage), t.defaultLanguage) {
+		if language, tmpl := t.lookup(language, reason); tmpl != nil {
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, params); err != nil {
+				return "", "", false
+			}
+			return buf.String(), language, true
+		}
+	}
+	return "", "", false
+}
+
+// Translate 翻译错误，返回消息替换为对应语言的新错误；没有对应消息时原样返回
+func (t *Translator) Translate(err error, acceptLanguage string) error {
+	se := errors.FromError(err)
+	if se == nil {
+		return err
+	}
+	message, _, ok := t.Message(se.Reason, se.Metadata, acceptLanguage)
+	if !ok {
+		return err
+	}
+	translated := errors.Clone(se)
+	translated.Message = message
+	return translated
+}
+
+// lookup 查找消息模板，精确匹配失败时回退到基础语言（如 zh-TW -> zh）
+func (t *Translator) lookup(language, reason string) (string, *template.Template) {
+	if tmpl := t.messages[language][reason]; tmpl != nil {
+		return language, tmpl
+	}
+	if base, _, ok := strings.Cut(language, "-"); ok {
+		if tmpl := t.messages[base][reason]; tmpl != nil {
+			return base, tmpl
+		}
+	}
+	return "", nil
+}
+
+// ParseAcceptLanguage 解析 Accept-Language，按权重从高到低返回规范化后的语言标签
+// 例如 "en-US,en;q=0.8,zh-CN;q=0.9" -> [en-us zh-cn en]
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		language string
+		q        float64
+	}
+	var languages []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			languages = append(languages, weighted{normalize(tag), q})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].q > languages[j].q })
+
+	tags := make([]string, len(languages))
+	for i, l := range languages {
+		tags[i] = l.language
+	}
+	return tags
+}
+
+// normalize 规范化语言标签：小写，下划线替换为连字符
+func normalize(language string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(language), "_", "-"))
+}
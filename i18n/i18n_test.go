@@ -0,0 +1,97 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+func newTestTranslator(t *testing.T) *Translator {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"zh-CN.yaml": "USER_NOT_FOUND: \"用户 {{.id}} 不存在\"\n",
+		"zh.yaml":    "ORDER_CLOSED: \"订单已关闭\"\n",
+		"en.json":    `{"USER_NOT_FOUND": "user {{.id}} not found", "ORDER_CLOSED": "order closed"}`,
+		"README.md":  "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tr := New("en")
+	if err := tr.LoadDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func TestMessage(t *testing.T) {
+	tr := newTestTranslator(t)
+	tests := []struct {
+		name           string
+		reason         string
+		acceptLanguage string
+		wantMessage    string
+		wantLanguage   string
+		wantOK         bool
+	}{
+		{name: "exact language", reason: "USER_NOT_FOUND", acceptLanguage: "zh-CN", wantMessage: "用户 42 不存在", wantLanguage: "zh-cn", wantOK: true},
+		{name: "case and underscore", reason: "USER_NOT_FOUND", acceptLanguage: "ZH_cn", wantMessage: "用户 42 不存在", wantLanguage: "zh-cn", wantOK: true},
+		{name: "base language", reason: "ORDER_CLOSED", acceptLanguage: "zh-TW", wantMessage: "订单已关闭", wantLanguage: "zh", wantOK: true},
+		{name: "by weight", reason: "USER_NOT_FOUND", acceptLanguage: "fr;q=0.9,zh-CN;q=0.8,en;q=0.5", wantMessage: "用户 42 不存在", wantLanguage: "zh-cn", wantOK: true},
+		{name: "default language", reason: "USER_NOT_FOUND", acceptLanguage: "fr", wantMessage: "user 42 not found", wantLanguage: "en", wantOK: true},
+		{name: "no header", reason: "ORDER_CLOSED", wantMessage: "order closed", wantLanguage: "en", wantOK: true},
+		{name: "unknown reason", reason: "UNKNOWN", acceptLanguage: "zh-CN"},
+		{name: "empty reason", acceptLanguage: "zh-CN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, language, ok := tr.Message(tt.reason, map[string]string{"id": "42"}, tt.acceptLanguage)
+			if message != tt.wantMessage || language != tt.wantLanguage || ok != tt.wantOK {
+				t.Fatalf("Message() = %q, %q, %v, want %q, %q, %v", message, language, ok, tt.wantMessage, tt.wantLanguage, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tr := newTestTranslator(t)
+	err := errors.NotFound("USER_NOT_FOUND", "user not found").WithMetadata(map[string]string{"id": "7"})
+
+	translated := errors.FromError(tr.Translate(err, "zh-CN"))
+	if translated.Message != "用户 7 不存在" || translated.Reason != "USER_NOT_FOUND" || translated.Code != 404 {
+		t.Fatalf("Translate() = %v", translated)
+	}
+	if err.Message != "user not found" {
+		t.Fatalf("original error should not be modified, got %q", err.Message)
+	}
+
+	untranslated := errors.BadRequest("UNKNOWN", "bad request")
+	if got := tr.Translate(untranslated, "zh-CN"); got != error(untranslated) {
+		t.Fatalf("Translate() should return the original error, got %v", got)
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"zh-CN", []string{"zh-cn"}},
+		{"en-US,en;q=0.8,zh-CN;q=0.9", []string{"en-us", "zh-cn", "en"}},
+		{"*, fr;q=0.5", []string{"fr"}},
+		{"de;q=0, ja", []string{"ja"}},
+		{"en;q=abc, zh_TW;q=0.1", []string{"en", "zh-tw"}},
+	}
+	for _, tt := range tests {
+		if got := ParseAcceptLanguage(tt.header); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/addls/go-boot/i18n"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+// I18n 返回一个错误消息翻译中间件
+// 按请求头（gRPC 元数据）accept-language 将错误消息翻译为对应语言，用于 gRPC 服务器；
+// HTTP 错误由 response.ErrorEncoder 翻译
+func I18n(translator *i18n.Translator) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			reply, err := handler(ctx, req)
+			if err != nil {
+				if info, ok := transport.FromServerContext(ctx); ok {
+					err = translator.Translate(err, info.RequestHeader().Get(i18n.HeaderAcceptLanguage))
+				}
+			}
+			return reply, err
+		}
+	}
+}
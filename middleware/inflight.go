@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+// InFlightTracker 处理中请求统计
+// 用于优雅关闭时输出仍未完成的请求
+type InFlightTracker struct {
+	mu         sync.Mutex
+	operations map[string]int
+}
+
+// NewInFlightTracker 创建处理中请求统计
+func NewInFlightTracker() *InFlightTracker {
+	return &InFlightTracker{operations: make(map[string]int)}
+}
+
+// Count 返回处理中的请求总数
+func (t *InFlightTracker) Count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	total := 0
+	for _, n := range t.operations {
+		total += n
+	}
+	return total
+}
+
+// Snapshot 返回按 operation 统计的处理中请求数
+func (t *InFlightTracker) Snapshot() map[string]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	snapshot := make(map[string]int, len(t.operations))
+	for op, n := range t.operations {
+		snapshot[op] = n
+	}
+	return snapshot
+}
+
+func (t *InFlightTracker) add(operation string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.operations[operation]++
+}
+
+func (t *InFlightTracker) done(operation string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.operations[operation]--; t.operations[operation] <= 0 {
+		delete(t.operations, operation)
+	}
+}
+
+// InFlight 返回一个处理中请求统计中间件
+func InFlight(tracker *InFlightTracker) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			var operation string
+			if info, ok := transport.FromServerContext(ctx); ok {
+				operation = info.Kind().String() + " " + info.Operation()
+			}
+			tracker.add(operation)
+			defer tracker.done(operation)
+			return handler(ctx, req)
+		}
+	}
+}
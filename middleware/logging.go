@@ -36,6 +36,7 @@ func Logging(logger log.Logger) middleware.Middleware {
 				helper.Log(log.LevelError,
 					"kind", kind,
 					"operation", operation,
+					"request_id", RequestIDFromContext(ctx),
 					"code", code,
 					"reason", reason,
 					"latency", time.Since(startTime).Seconds(),
@@ -45,6 +46,57 @@ func Logging(logger log.Logger) middleware.Middleware {
 				helper.Log(log.LevelInfo,
 					"kind", kind,
 					"operation", operation,
+					"request_id", RequestIDFromContext(ctx),
+					"code", code,
+					"reason", reason,
+					"latency", time.Since(startTime).Seconds(),
+				)
+			}
+			return reply, err
+		}
+	}
+}
+
+// ClientLogging 返回客户端日志中间件，记录调用下游服务的请求信息
+func ClientLogging(logger log.Logger) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			var (
+				code      int
+				reason    string
+				kind      string
+				operation string
+				endpoint  string
+			)
+			startTime := time.Now()
+			if info, ok := transport.FromClientContext(ctx); ok {
+				kind = info.Kind().String()
+				operation = info.Operation()
+				endpoint = info.Endpoint()
+			}
+			reply, err := handler(ctx, req)
+			if se := errors.FromError(err); se != nil {
+				code = int(se.Code)
+				reason = se.Reason
+			}
+			helper := log.NewHelper(logger)
+			if err != nil {
+				helper.Log(log.LevelError,
+					"kind", kind,
+					"component", "client",
+					"operation", operation,
+					"endpoint", endpoint,
+					"code", code,
+					"reason", reason,
+					"latency", time.Since(startTime).Seconds(),
+					"error", err,
+				)
+			} else {
+				helper.Log(log.LevelInfo,
+					"kind", kind,
+					"component", "client",
+					"operation", operation,
+					"endpoint", endpoint,
 					"code", code,
 					"reason", reason,
 					"latency", time.Since(startTime).Seconds(),
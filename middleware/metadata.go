@@ -11,3 +11,9 @@ import (
 func Metadata() middleware.Middleware {
 	return metadata.Server()
 }
+
+// ClientMetadata 返回客户端元数据中间件
+// 与 Metadata 配合使用，将当前请求的全局元数据传递给下游服务
+func ClientMetadata() middleware.Middleware {
+	return metadata.Client()
+}
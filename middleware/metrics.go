@@ -10,3 +10,8 @@ import (
 func Metrics() middleware.Middleware {
 	return metrics.Server()
 }
+
+// ClientMetrics 返回客户端监控指标中间件
+func ClientMetrics() middleware.Middleware {
+	return metrics.Client()
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/addls/go-boot/common"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/google/uuid"
+)
+
+type requestIDKey struct{}
+
+// RequestID 返回一个请求 ID 中间件
+// 优先使用请求头 X-Request-Id，不存在时生成新的 ID，并写回响应头
+func RequestID() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			if info, ok := transport.FromServerContext(ctx); ok {
+				id := info.RequestHeader().Get(common.HeaderRequestID)
+				if id == "" {
+					id = uuid.NewString()
+				}
+				info.ReplyHeader().Set(common.HeaderRequestID, id)
+				ctx = context.WithValue(ctx, requestIDKey{}, id)
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// RequestIDFromContext 从上下文中获取请求 ID
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/addls/go-boot/common"
+	"github.com/addls/go-boot/response"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+// ErrorDetails 返回一个 gRPC 错误详情中间件
+// 为返回的错误附带 google.rpc 错误详情（ErrorInfo、BadRequest、RetryInfo、RequestInfo），
+// 与 HTTP 统一结构保持一致，客户端通过 response.FromGRPCError 还原为 *errors.Error
+func ErrorDetails() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			reply, err := handler(ctx, req)
+			if err != nil {
+				var requestID string
+				if info, ok := transport.FromServerContext(ctx); ok {
+					requestID = info.ReplyHeader().Get(common.HeaderRequestID)
+				}
+				err = response.GRPCError(err, requestID)
+			}
+			return reply, err
+		}
+	}
+}
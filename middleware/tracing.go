@@ -10,3 +10,8 @@ import (
 func Tracing() middleware.Middleware {
 	return tracing.Server()
 }
+
+// ClientTracing 返回客户端链路追踪中间件
+func ClientTracing() middleware.Middleware {
+	return tracing.Client()
+}
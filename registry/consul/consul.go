@@ -1,7 +1,9 @@
 package consul
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/addls/go-boot/config"
@@ -10,50 +12,181 @@ import (
 	consulAPI "github.com/hashicorp/consul/api"
 )
 
+// consul 默认配置
+const (
+	defaultInterval        = 10 * time.Second
+	defaultDeregisterAfter = 10 * time.Minute
+	checkTimeout           = "5s"
+)
+
+// Registry consul 注册中心，持有 consul 客户端，同时实现 Registrar 和 Discovery 接口
+type Registry struct {
+	*consulRegistry.Registry
+
+	client *consulAPI.Client
+}
+
 // NewRegistrar 创建 consul 注册中心
 func NewRegistrar(cfg *config.Discovery) (registry.Registrar, error) {
+	return New(cfg)
+}
+
+// NewDiscovery 创建 consul 服务发现客户端
+func NewDiscovery(cfg *config.Discovery) (registry.Discovery, error) {
+	return New(cfg)
+}
+
+// New 创建 consul Registry
+func New(cfg *config.Discovery) (*Registry, error) {
 	client, err := newClient(cfg)
 	if err != nil {
 		return nil, err
 	}
-	return consulRegistry.New(client), nil
+	opts, err := options(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Registry{Registry: consulRegistry.New(client, opts...), client: client}, nil
 }
 
-// NewDiscovery 创建 consul 服务发现客户端
-func NewDiscovery(cfg *config.Discovery) (registry.Discovery, error) {
-	client, err := newClient(cfg)
+// Health 检查与 consul 的连接
+func (r *Registry) Health(ctx context.Context) error {
+	_, err := r.client.Status().LeaderWithQueryOptions((&consulAPI.QueryOptions{}).WithContext(ctx))
+	return err
+}
+
+// Services 列出已注册的服务名称（不包括 consul 自身）
+func (r *Registry) Services(ctx context.Context) ([]string, error) {
+	catalog, _, err := r.client.Catalog().Services((&consulAPI.QueryOptions{}).WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
-	// consul Registry 同时实现了 Registrar 和 Discovery 接口
-	return consulRegistry.New(client), nil
+	services := make([]string, 0, len(catalog))
+	for name := range catalog {
+		if name != "consul" {
+			services = append(services, name)
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// Close consul 客户端基于 HTTP，没有需要释放的长连接，心跳在注销时停止
+func (r *Registry) Close() error {
+	return nil
+}
+
+// options 将配置映射为 consul Registry 选项
+func options(cfg *config.Discovery) ([]consulRegistry.Option, error) {
+	opts := []consulRegistry.Option{consulRegistry.WithTimeout(timeout(cfg))}
+
+	hc := cfg.HealthCheck
+	if hc == nil {
+		return opts, nil
+	}
+	interval := checkInterval(hc)
+	deregisterAfter := parseDuration(hc.DeregisterAfter, defaultDeregisterAfter)
+	opts = append(opts,
+		consulRegistry.WithHealthCheckInterval(int(interval.Seconds())),
+		consulRegistry.WithDeregisterCriticalServiceAfter(int(deregisterAfter.Seconds())),
+	)
+
+	// 自定义检查只在开启健康检查时生效，http、grpc 检查会与 endpoint 的 tcp 检查一起注册
+	check := &consulAPI.AgentServiceCheck{
+		Interval:                       interval.String(),
+		Timeout:                        checkTimeout,
+		DeregisterCriticalServiceAfter: deregisterAfter.String(),
+	}
+	switch hc.Type {
+	case "":
+		// 使用默认的 tcp 检查 + ttl 心跳
+	case "ttl":
+		opts = append(opts, consulRegistry.WithHealthCheck(false), consulRegistry.WithHeartbeat(true))
+	case "tcp":
+		opts = append(opts, consulRegistry.WithHealthCheck(true), consulRegistry.WithHeartbeat(false))
+	case "http":
+		if hc.HTTP == "" {
+			return nil, fmt.Errorf("consul http health check address cannot be empty")
+		}
+		check.HTTP = hc.HTTP
+		opts = append(opts, consulRegistry.WithHealthCheck(true), consulRegistry.WithHeartbeat(false), consulRegistry.WithServiceCheck(check))
+	case "grpc":
+		if hc.GRPC == "" {
+			return nil, fmt.Errorf("consul grpc health check address cannot be empty")
+		}
+		check.GRPC = hc.GRPC
+		check.GRPCUseTLS = cfg.TLS != nil
+		opts = append(opts, consulRegistry.WithHealthCheck(true), consulRegistry.WithHeartbeat(false), consulRegistry.WithServiceCheck(check))
+	case "none":
+		opts = append(opts, consulRegistry.WithHealthCheck(false), consulRegistry.WithHeartbeat(false))
+	default:
+		return nil, fmt.Errorf("unsupported consul health check type: %s", hc.Type)
+	}
+	return opts, nil
+}
+
+// checkInterval 检查间隔，consul 只支持整秒，不足 1s 时使用 1s
+// 配置了 ttl 且使用心跳时，ttl 心跳超时为两倍间隔，因此间隔取 ttl 的一半
+func checkInterval(hc *config.HealthCheck) time.Duration {
+	interval := parseDuration(hc.Interval, defaultInterval)
+	if hc.TTL != "" && (hc.Type == "" || hc.Type == "ttl") {
+		interval = parseDuration(hc.TTL, 2*defaultInterval) / 2
+	}
+	return max(interval.Truncate(time.Second), time.Second)
 }
 
 // newClient 创建 consul 客户端
+// 配置多个地址时按顺序使用，请求连接失败时切换到下一个地址
 func newClient(cfg *config.Discovery) (*consulAPI.Client, error) {
 	if len(cfg.Endpoints) == 0 {
 		return nil, fmt.Errorf("consul endpoints cannot be empty")
 	}
 
-	// 解析超时时间
-	timeout := 5 * time.Second
-	if cfg.Timeout != "" {
-		duration, err := time.ParseDuration(cfg.Timeout)
-		if err == nil {
-			timeout = duration
+	consulConfig := consulAPI.DefaultConfig()
+	consulConfig.Address = cfg.Endpoints[0]
+	consulConfig.Token = cfg.Token
+	consulConfig.Datacenter = cfg.Datacenter
+	consulConfig.Namespace = cfg.Namespace
+	if cfg.Username != "" {
+		consulConfig.HttpAuth = &consulAPI.HttpBasicAuth{Username: cfg.Username, Password: cfg.Password}
+	}
+	if cfg.TLS != nil {
+		consulConfig.Scheme = "https"
+		consulConfig.TLSConfig = consulAPI.TLSConfig{
+			Address:            cfg.TLS.ServerName,
+			CAFile:             cfg.TLS.CAFile,
+			CertFile:           cfg.TLS.CertFile,
+			KeyFile:            cfg.TLS.KeyFile,
+			InsecureSkipVerify: cfg.TLS.InsecureSkipVerify,
 		}
 	}
+	if len(cfg.Endpoints) > 1 {
+		httpClient, err := consulAPI.NewHttpClient(consulConfig.Transport, consulConfig.TLSConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create consul client: %w", err)
+		}
+		httpClient.Transport = newFailoverTransport(httpClient.Transport, cfg.Endpoints)
+		consulConfig.HttpClient = httpClient
+	}
 
-	// 创建 consul 客户端配置
-	consulConfig := consulAPI.DefaultConfig()
-	consulConfig.Address = cfg.Endpoints[0] // consul 通常只需要一个地址
-	consulConfig.WaitTime = timeout
-
-	// 创建 consul 客户端
 	client, err := consulAPI.NewClient(consulConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create consul client: %w", err)
 	}
-
 	return client, nil
 }
+
+// timeout 解析超时时间（默认 5s）
+func timeout(cfg *config.Discovery) time.Duration {
+	return parseDuration(cfg.Timeout, 5*time.Second)
+}
+
+func parseDuration(s string, def time.Duration) time.Duration {
+	if s == "" {
+		return def
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d
+	}
+	return def
+}
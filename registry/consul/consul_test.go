@@ -0,0 +1,131 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/addls/go-boot/config"
+	consulAPI "github.com/hashicorp/consul/api"
+)
+
+// fakeConsul 模拟 consul agent 的 leader 查询与服务注册
+type fakeConsul struct {
+	mu       sync.Mutex
+	services map[string]string // 服务 ID -> 服务名
+}
+
+func newFakeConsul(t *testing.T) (*fakeConsul, *httptest.Server) {
+	f := &fakeConsul{services: make(map[string]string)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method + " " + r.URL.Path {
+	case "GET /v1/status/leader":
+		_ = json.NewEncoder(w).Encode("10.0.0.1:8300")
+	case "PUT /v1/agent/service/register":
+		var svc consulAPI.AgentServiceRegistration
+		if err := json.NewDecoder(r.Body).Decode(&svc); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		f.services[svc.ID] = svc.Name
+		f.mu.Unlock()
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// closedEndpoint 返回一个已关闭的地址，连接会被拒绝
+func closedEndpoint() string {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	return srv.URL
+}
+
+func TestFailover(t *testing.T) {
+	f, srv := newFakeConsul(t)
+	r, err := New(&config.Discovery{Endpoints: []string{closedEndpoint(), srv.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Health(context.Background()); err != nil {
+		t.Fatalf("Health() error = %v", err)
+	}
+	// 带请求体的请求切换地址后重放
+	reg := &consulAPI.AgentServiceRegistration{ID: "order-1", Name: "order"}
+	if err := r.client.Agent().ServiceRegister(reg); err != nil {
+		t.Fatalf("ServiceRegister() error = %v", err)
+	}
+	f.mu.Lock()
+	name := f.services["order-1"]
+	f.mu.Unlock()
+	if name != "order" {
+		t.Fatalf("service not registered on the second endpoint, got %q", name)
+	}
+}
+
+func TestFailoverTransport(t *testing.T) {
+	_, good := newFakeConsul(t)
+	bad := closedEndpoint()
+
+	tests := []struct {
+		name        string
+		endpoints   []string
+		wantErr     bool
+		wantCurrent int
+	}{
+		{name: "first available", endpoints: []string{good.URL, bad}, wantCurrent: 0},
+		{name: "switch to next", endpoints: []string{bad, good.URL}, wantCurrent: 1},
+		{name: "all unavailable", endpoints: []string{bad, bad}, wantErr: true, wantCurrent: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := newFailoverTransport(http.DefaultTransport, tt.endpoints)
+			client := &http.Client{Transport: transport}
+			resp, err := client.Get("http://consul/v1/status/leader")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			if transport.current != tt.wantCurrent {
+				t.Fatalf("current = %d, want %d", transport.current, tt.wantCurrent)
+			}
+		})
+	}
+}
+
+func TestCheckInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		hc   config.HealthCheck
+		want time.Duration
+	}{
+		{name: "default", want: 10 * time.Second},
+		{name: "interval", hc: config.HealthCheck{Interval: "3s"}, want: 3 * time.Second},
+		{name: "sub-second interval", hc: config.HealthCheck{Interval: "500ms"}, want: time.Second},
+		{name: "fractional interval", hc: config.HealthCheck{Interval: "2500ms"}, want: 2 * time.Second},
+		{name: "ttl", hc: config.HealthCheck{Type: "ttl", Interval: "3s", TTL: "30s"}, want: 15 * time.Second},
+		{name: "ttl with default type", hc: config.HealthCheck{TTL: "8s"}, want: 4 * time.Second},
+		{name: "short ttl", hc: config.HealthCheck{Type: "ttl", TTL: "1s"}, want: time.Second},
+		{name: "ttl ignored without heartbeat", hc: config.HealthCheck{Type: "http", Interval: "5s", TTL: "30s"}, want: 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkInterval(&tt.hc); got != tt.want {
+				t.Fatalf("checkInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package consul
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// failoverTransport 按顺序使用 consul 地址，连接失败时切换到下一个地址重试
+// 之后的请求直接使用切换后的地址，直到该地址也不可用
+type failoverTransport struct {
+	base  http.RoundTripper
+	hosts []string
+
+	mu      sync.Mutex
+	current int
+}
+
+// newFailoverTransport 创建故障转移的 RoundTripper，地址可以带 http:// 或 https:// 前缀
+func newFailoverTransport(base http.RoundTripper, endpoints []string) *failoverTransport {
+	hosts := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if _, host, ok := strings.Cut(endpoint, "://"); ok {
+			endpoint = host
+		}
+		hosts = append(hosts, endpoint)
+	}
+	return &failoverTransport{base: base, hosts: hosts}
+}
+
+// RoundTrip 只在连接错误时切换地址，consul 返回的错误响应直接交给调用方
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	start := t.current
+	t.mu.Unlock()
+
+	var lastErr error
+	for i := range t.hosts {
+		idx := (start + i) % len(t.hosts)
+		r := req.Clone(req.Context())
+		r.URL.Host = t.hosts[idx]
+		r.Host = ""
+		if i > 0 && req.Body != nil {
+			// 请求体已被上一次尝试读取，无法重放时返回上一次的错误
+			if req.GetBody == nil {
+				return nil, lastErr
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, lastErr
+			}
+			r.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		if err == nil {
+			if idx != start {
+				t.mu.Lock()
+				t.current = idx
+				t.mu.Unlock()
+				log.Warnf("consul endpoint %s is unavailable, switched to %s", t.hosts[start], t.hosts[idx])
+			}
+			return resp, nil
+		}
+		lastErr = err
+		if req.Context().Err() != nil {
+			break
+		}
+	}
+	return nil, lastErr
+}
@@ -1,7 +1,10 @@
 package etcd
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/addls/go-boot/config"
@@ -10,23 +13,83 @@ import (
 	etcdClient "go.etcd.io/etcd/client/v3"
 )
 
+// Registry etcd 注册中心，持有 etcd 客户端，同时实现 Registrar 和 Discovery 接口
+type Registry struct {
+	*etcdRegistry.Registry
+
+	client    *etcdClient.Client
+	namespace string
+}
+
+// defaultNamespace etcd Registry 默认的 key 前缀，与 Kratos etcd Registry 一致
+const defaultNamespace = "/microservices"
+
 // NewRegistrar 创建 etcd 注册中心
 func NewRegistrar(cfg *config.Discovery) (registry.Registrar, error) {
+	return New(cfg)
+}
+
+// NewDiscovery 创建 etcd 服务发现客户端
+func NewDiscovery(cfg *config.Discovery) (registry.Discovery, error) {
+	return New(cfg)
+}
+
+// New 创建 etcd Registry
+func New(cfg *config.Discovery) (*Registry, error) {
 	client, err := newClient(cfg)
 	if err != nil {
 		return nil, err
 	}
-	return etcdRegistry.New(client), nil
+	namespace := defaultNamespace
+	if cfg.Namespace != "" {
+		namespace = cfg.Namespace
+	}
+	return &Registry{Registry: etcdRegistry.New(client, options(cfg)...), client: client, namespace: namespace}, nil
 }
 
-// NewDiscovery 创建 etcd 服务发现客户端
-func NewDiscovery(cfg *config.Discovery) (registry.Discovery, error) {
-	client, err := newClient(cfg)
+// Health 检查与 etcd 的连接
+func (r *Registry) Health(ctx context.Context) error {
+	_, err := r.client.Get(ctx, "health", etcdClient.WithCountOnly())
+	return err
+}
+
+// Services 列出已注册的服务名称（key 格式为 <namespace>/<service>/<id>）
+func (r *Registry) Services(ctx context.Context) ([]string, error) {
+	prefix := r.namespace + "/"
+	resp, err := r.client.Get(ctx, prefix, etcdClient.WithPrefix(), etcdClient.WithKeysOnly())
 	if err != nil {
 		return nil, err
 	}
-	// etcd Registry 同时实现了 Registrar 和 Discovery 接口
-	return etcdRegistry.New(client), nil
+	seen := make(map[string]bool)
+	var services []string
+	for _, kv := range resp.Kvs {
+		name, _, _ := strings.Cut(strings.TrimPrefix(string(kv.Key), prefix), "/")
+		if name != "" && !seen[name] {
+			seen[name] = true
+			services = append(services, name)
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// Close 关闭 etcd 客户端
+func (r *Registry) Close() error {
+	return r.client.Close()
+}
+
+// options 将配置映射为 etcd Registry 选项
+func options(cfg *config.Discovery) []etcdRegistry.Option {
+	var opts []etcdRegistry.Option
+	if cfg.Namespace != "" {
+		opts = append(opts, etcdRegistry.Namespace(cfg.Namespace))
+	}
+	if cfg.HealthCheck != nil && cfg.HealthCheck.TTL != "" {
+		if ttl, err := time.ParseDuration(cfg.HealthCheck.TTL); err == nil {
+			opts = append(opts, etcdRegistry.RegisterTTL(ttl))
+		}
+	}
+	return opts
 }
 
 // newClient 创建 etcd 客户端
@@ -44,11 +107,22 @@ func newClient(cfg *config.Discovery) (*etcdClient.Client, error) {
 		}
 	}
 
-	// 创建 etcd 客户端
-	client, err := etcdClient.New(etcdClient.Config{
+	etcdConfig := etcdClient.Config{
 		Endpoints:   cfg.Endpoints,
 		DialTimeout: timeout,
-	})
+		Username:    cfg.Username,
+		Password:    cfg.Password,
+	}
+	if cfg.TLS != nil {
+		tlsConfig, err := cfg.TLS.Config()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load etcd tls config: %w", err)
+		}
+		etcdConfig.TLS = tlsConfig
+	}
+
+	// 创建 etcd 客户端
+	client, err := etcdClient.New(etcdConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create etcd client: %w", err)
 	}
@@ -0,0 +1,125 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/addls/go-boot/config"
+	"github.com/addls/go-boot/registry/static"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/registry"
+	"gopkg.in/yaml.v3"
+)
+
+// pollInterval 检查文件变化的间隔
+const pollInterval = time.Second
+
+var _ registry.Discovery = (*Discovery)(nil)
+
+// Discovery 基于文件的服务发现
+// 文件为 YAML 或 JSON 格式的服务名到地址列表的映射，修改文件后自动生效：
+//
+//	service-order:
+//	  - grpc://127.0.0.1:9000
+//	  - http://127.0.0.1:8000
+type Discovery struct {
+	*static.Discovery
+
+	path    string
+	last    []byte    // 最近一次成功加载的内容
+	modTime time.Time // 最近一次读取时的修改时间
+	size    int64     // 最近一次读取时的文件大小
+	lastErr string    // 最近一次加载的错误，用于避免重复记录
+	cancel  context.CancelFunc
+}
+
+// NewDiscovery 根据 discovery.file 创建基于文件的服务发现
+func NewDiscovery(cfg *config.Discovery) (registry.Discovery, error) {
+	if cfg.File == "" {
+		return nil, fmt.Errorf("discovery file cannot be empty")
+	}
+	return New(cfg.File)
+}
+
+// New 加载服务地址文件并监听变化
+func New(path string) (*Discovery, error) {
+	d := &Discovery{Discovery: static.New(nil), path: path}
+	if _, err := d.reload(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	go d.watch(ctx)
+	return d, nil
+}
+
+// Close 停止监听文件
+func (d *Discovery) Close() error {
+	d.cancel()
+	return nil
+}
+
+// watch 定期检查文件，变化时重新加载
+func (d *Discovery) watch(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.check()
+		}
+	}
+}
+
+// check 重新加载文件并记录日志
+// 加载失败（如编辑过程中文件格式暂时不正确、文件被删除）时保留上一次的地址，同一错误只记录一次
+func (d *Discovery) check() {
+	changed, err := d.reload()
+	var msg string
+	if err != nil {
+		msg = err.Error()
+	}
+	if msg != d.lastErr {
+		d.lastErr = msg
+		if err != nil {
+			log.Warnf("failed to reload discovery file %s, keep the last services: %v", d.path, err)
+			return
+		}
+	}
+	if changed {
+		log.Infof("discovery file %s reloaded", d.path)
+	}
+}
+
+// reload 文件修改时间或大小变化时重新读取，内容变化时更新服务地址
+func (d *Discovery) reload() (bool, error) {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return false, err
+	}
+	if d.last != nil && info.ModTime().Equal(d.modTime) && info.Size() == d.size {
+		return false, nil
+	}
+	data, err := os.ReadFile(d.path)
+	if err != nil {
+		return false, err
+	}
+	d.modTime, d.size = info.ModTime(), info.Size()
+	if d.last != nil && bytes.Equal(data, d.last) {
+		return false, nil
+	}
+	// JSON 是 YAML 的子集，统一使用 YAML 解析
+	var services map[string][]string
+	if err := yaml.Unmarshal(data, &services); err != nil {
+		return false, fmt.Errorf("parse discovery file failed: %w", err)
+	}
+	d.last = data
+	d.Update(services)
+	return true, nil
+}
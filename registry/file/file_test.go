@@ -0,0 +1,91 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// writeFile 写入文件并推进修改时间，避免文件系统时间精度导致变化被忽略
+func writeFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetLogger(log.NewStdLogger(&buf))
+	t.Cleanup(func() { log.SetLogger(log.NewStdLogger(os.Stderr)) })
+
+	path := filepath.Join(t.TempDir(), "services.yaml")
+	now := time.Now()
+	writeFile(t, path, "order:\n  - grpc://127.0.0.1:9000\n", now)
+	d, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 停止后台轮询，由测试直接调用 check
+	_ = d.Close()
+
+	tests := []struct {
+		name     string
+		content  string // 为空时不修改文件
+		remove   bool
+		wantLogs []string
+		wantAddr string
+	}{
+		{name: "unchanged", wantAddr: "127.0.0.1:9000"},
+		{name: "invalid", content: "order: [", wantLogs: []string{"failed to reload"}, wantAddr: "127.0.0.1:9000"},
+		{name: "same error is logged once", content: "order: [ ", wantAddr: "127.0.0.1:9000"},
+		{name: "removed", remove: true, wantLogs: []string{"failed to reload"}, wantAddr: "127.0.0.1:9000"},
+		{name: "removed again", wantAddr: "127.0.0.1:9000"},
+		{name: "recovered", content: "order:\n  - grpc://127.0.0.1:9001\n", wantLogs: []string{"reloaded"}, wantAddr: "127.0.0.1:9001"},
+		{name: "same content", content: "order:\n  - grpc://127.0.0.1:9001\n", wantAddr: "127.0.0.1:9001"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			switch {
+			case tt.remove:
+				if err := os.Remove(path); err != nil {
+					t.Fatal(err)
+				}
+			case tt.content != "":
+				writeFile(t, path, tt.content, now.Add(time.Duration(i+1)*time.Second))
+			}
+			d.check()
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if buf.Len() == 0 {
+				lines = nil
+			}
+			if len(lines) != len(tt.wantLogs) {
+				t.Fatalf("logs = %q, want %q", lines, tt.wantLogs)
+			}
+			for j, want := range tt.wantLogs {
+				if !strings.Contains(lines[j], want) {
+					t.Fatalf("log = %q, want containing %q", lines[j], want)
+				}
+			}
+
+			got, err := d.GetService(context.Background(), "order")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 1 || !strings.Contains(got[0].Endpoints[0], tt.wantAddr) {
+				t.Fatalf("GetService() = %+v, want %s", got, tt.wantAddr)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/addls/go-boot/config"
+	"github.com/go-kratos/kratos/v2/registry"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// namespaceFile Pod 内 ServiceAccount 的命名空间文件
+	namespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+	// LabelVersion EndpointSlice 上表示实例版本的标签，用于客户端按版本过滤
+	// EndpointSlice 控制器会把 Service 的标签复制到 EndpointSlice 上，EndpointSlice 中不包含 Pod 的标签
+	LabelVersion = "app.kubernetes.io/version"
+)
+
+var _ registry.Discovery = (*Discovery)(nil)
+
+// Discovery 基于 Kubernetes EndpointSlice 的服务发现
+// 服务注册由 Kubernetes 完成（Service + 就绪探针），这里只负责发现
+// 服务名格式为 <service> 或 <service>.<namespace>，端口按名称确定协议：grpc、grpc-* 为 grpc，http、http-* 为 http
+type Discovery struct {
+	clientset kubernetes.Interface
+	namespace string
+}
+
+// NewDiscovery 创建 Kubernetes 服务发现客户端
+// 配置 kubeconfig 时使用该文件认证，否则优先使用集群内 ServiceAccount，集群外回退到默认 kubeconfig（KUBECONFIG 或 ~/.kube/config）
+func NewDiscovery(cfg *config.Discovery) (registry.Discovery, error) {
+	restConfig, err := restConfig(cfg.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubernetes config: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	return New(clientset, cfg.Namespace), nil
+}
+
+// New 使用已有的 clientset 创建服务发现客户端，namespace 为空时使用当前 Pod 所在命名空间（集群外为 default）
+func New(clientset kubernetes.Interface, namespace string) *Discovery {
+	if namespace == "" {
+		namespace = currentNamespace()
+	}
+	return &Discovery{clientset: clientset, namespace: namespace}
+}
+
+// GetService 获取服务的就绪实例
+func (d *Discovery) GetService(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	name, namespace := d.parse(serviceName)
+	list, err := d.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices := make([]*discoveryv1.EndpointSlice, len(list.Items))
+	for i := range list.Items {
+		slices[i] = &list.Items[i]
+	}
+	return instances(serviceName, slices), nil
+}
+
+// Services 列出默认命名空间中有 EndpointSlice 的服务名称
+func (d *Discovery) Services(ctx context.Context) ([]string, error) {
+	list, err := d.clientset.DiscoveryV1().EndpointSlices(d.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var services []string
+	for _, slice := range list.Items {
+		if name := slice.Labels[discoveryv1.LabelServiceName]; name != "" && !seen[name] {
+			seen[name] = true
+			services = append(services, name)
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// Health 检查与 Kubernetes API Server 的连接
+func (d *Discovery) Health(context.Context) error {
+	_, err := d.clientset.Discovery().ServerVersion()
+	return err
+}
+
+// Watch 监听服务实例变化
+func (d *Discovery) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	name, namespace := d.parse(serviceName)
+	return newWatcher(ctx, d.clientset, serviceName, name, namespace)
+}
+
+// parse 解析服务名与命名空间
+func (d *Discovery) parse(serviceName string) (name, namespace string) {
+	if name, namespace, ok := strings.Cut(serviceName, "."); ok {
+		return name, namespace
+	}
+	return serviceName, d.namespace
+}
+
+// instances 将 EndpointSlice 转换为服务实例，只保留就绪的地址
+// 同一地址在多个 EndpointSlice 中出现时（如不同端口拆分到不同 EndpointSlice）合并为一个实例，按协议、地址与端口去重
+func instances(serviceName string, slices []*discoveryv1.EndpointSlice) []*registry.ServiceInstance {
+	byAddr := make(map[string]*registry.ServiceInstance)
+	seen := make(map[string]bool)
+	var items []*registry.ServiceInstance
+	for _, slice := range slices {
+		if slice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
+		}
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+				continue
+			}
+			for _, addr := range ep.Addresses {
+				var endpoints []string
+				for _, port := range slice.Ports {
+					if port.Port == nil {
+						continue
+					}
+					endpoint := scheme(port) + "://" + net.JoinHostPort(addr, strconv.Itoa(int(*port.Port)))
+					if !seen[endpoint] {
+						seen[endpoint] = true
+						endpoints = append(endpoints, endpoint)
+					}
+				}
+				if len(endpoints) == 0 {
+					continue
+				}
+				if ins, ok := byAddr[addr]; ok {
+					ins.Endpoints = append(ins.Endpoints, endpoints...)
+					continue
+				}
+
+				id := addr
+				metadata := make(map[string]string)
+				if ep.TargetRef != nil {
+					id = ep.TargetRef.Name
+					metadata["pod"] = ep.TargetRef.Name
+				}
+				if ep.Zone != nil {
+					metadata["zone"] = *ep.Zone
+				}
+				if ep.NodeName != nil {
+					metadata["node"] = *ep.NodeName
+				}
+				ins := &registry.ServiceInstance{
+					ID:        id,
+					Name:      serviceName,
+					Version:   slice.Labels[LabelVersion],
+					Metadata:  metadata,
+					Endpoints: endpoints,
+				}
+				byAddr[addr] = ins
+				items = append(items, ins)
+			}
+		}
+	}
+	for _, ins := range items {
+		sort.Strings(ins.Endpoints)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
+
+// scheme 根据端口名称或 appProtocol 确定协议，默认为 grpc
+func scheme(port discoveryv1.EndpointPort) string {
+	name := ""
+	if port.AppProtocol != nil {
+		name = *port.AppProtocol
+	} else if port.Name != nil {
+		name = *port.Name
+	}
+	if name == "http" || strings.HasPrefix(name, "http-") {
+		return "http"
+	}
+	return "grpc"
+}
+
+// restConfig 加载 Kubernetes 客户端配置
+func restConfig(kubeconfig string) (*rest.Config, error) {
+	if kubeconfig != "" {
+		return clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	if cfg, err := rest.InClusterConfig(); err == nil {
+		return cfg, nil
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{},
+	).ClientConfig()
+}
+
+// currentNamespace 获取当前 Pod 所在命名空间，集群外为 default
+func currentNamespace() string {
+	if data, err := os.ReadFile(namespaceFile); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+	return metav1.NamespaceDefault
+}
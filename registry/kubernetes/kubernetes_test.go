@@ -0,0 +1,235 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// endpoint 创建 EndpointSlice 中的端点，pod 为空时没有 TargetRef
+func endpoint(addr, pod string, ready bool) discoveryv1.Endpoint {
+	ep := discoveryv1.Endpoint{
+		Addresses:  []string{addr},
+		Conditions: discoveryv1.EndpointConditions{Ready: ptr(ready)},
+	}
+	if pod != "" {
+		ep.TargetRef = &corev1.ObjectReference{Kind: "Pod", Name: pod}
+	}
+	return ep
+}
+
+// port 创建 EndpointSlice 中的端口
+func port(name string, p int32) discoveryv1.EndpointPort {
+	return discoveryv1.EndpointPort{Name: ptr(name), Port: ptr(p)}
+}
+
+// endpointSlice 创建 default 命名空间中属于 service 的 EndpointSlice
+func endpointSlice(name, service string, ports []discoveryv1.EndpointPort, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: service},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   endpoints,
+		Ports:       ports,
+	}
+}
+
+// summary 将实例转换为 ID -> Endpoints，便于比较
+func summary(instances []*registry.ServiceInstance) map[string][]string {
+	out := make(map[string][]string, len(instances))
+	for _, ins := range instances {
+		out[ins.ID] = ins.Endpoints
+	}
+	return out
+}
+
+func TestGetService(t *testing.T) {
+	grpcPort := []discoveryv1.EndpointPort{port("grpc", 9000)}
+	versioned := endpointSlice("order-v2", "order", grpcPort, endpoint("10.0.0.5", "order-v2-0", true))
+	versioned.Labels[LabelVersion] = "v2"
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		want    map[string][]string
+	}{
+		{name: "no slices", want: map[string][]string{}},
+		{
+			name: "ready only",
+			objects: []runtime.Object{endpointSlice("order-a", "order", grpcPort,
+				endpoint("10.0.0.1", "order-0", true),
+				endpoint("10.0.0.2", "order-1", false),
+			)},
+			want: map[string][]string{"order-0": {"grpc://10.0.0.1:9000"}},
+		},
+		{
+			name: "ports and protocols",
+			objects: []runtime.Object{endpointSlice("order-a", "order",
+				[]discoveryv1.EndpointPort{port("grpc", 9000), port("http-api", 8000), {Name: ptr("web"), Port: ptr(int32(8080)), AppProtocol: ptr("http")}},
+				endpoint("10.0.0.1", "", true),
+			)},
+			want: map[string][]string{"10.0.0.1": {"grpc://10.0.0.1:9000", "http://10.0.0.1:8000", "http://10.0.0.1:8080"}},
+		},
+		{
+			name: "multiple slices",
+			objects: []runtime.Object{
+				endpointSlice("order-a", "order", grpcPort, endpoint("10.0.0.1", "order-0", true)),
+				endpointSlice("order-b", "order", grpcPort, endpoint("10.0.0.2", "order-1", true)),
+				endpointSlice("user-a", "user", grpcPort, endpoint("10.0.0.3", "user-0", true)),
+			},
+			want: map[string][]string{"order-0": {"grpc://10.0.0.1:9000"}, "order-1": {"grpc://10.0.0.2:9000"}},
+		},
+		{
+			name: "same address in slices with different ports",
+			objects: []runtime.Object{
+				endpointSlice("order-a", "order", grpcPort, endpoint("10.0.0.1", "order-0", true)),
+				endpointSlice("order-b", "order", []discoveryv1.EndpointPort{port("http", 8000)}, endpoint("10.0.0.1", "order-0", true)),
+				endpointSlice("order-c", "order", grpcPort, endpoint("10.0.0.1", "order-0", true)),
+			},
+			want: map[string][]string{"order-0": {"grpc://10.0.0.1:9000", "http://10.0.0.1:8000"}},
+		},
+		{
+			name:    "version label",
+			objects: []runtime.Object{versioned},
+			want:    map[string][]string{"order-v2-0": {"grpc://10.0.0.5:9000"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New(fake.NewSimpleClientset(tt.objects...), metav1.NamespaceDefault)
+			instances, err := d.GetService(context.Background(), "order")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := summary(instances); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("GetService() = %v, want %v", got, tt.want)
+			}
+			for _, ins := range instances {
+				if ins.Name != "order" {
+					t.Fatalf("instance name = %q, want order", ins.Name)
+				}
+				if ins.ID == "order-v2-0" && ins.Version != "v2" {
+					t.Fatalf("instance version = %q, want v2", ins.Version)
+				}
+			}
+		})
+	}
+}
+
+func TestGetServiceNamespace(t *testing.T) {
+	slice := endpointSlice("order-a", "order", []discoveryv1.EndpointPort{port("grpc", 9000)}, endpoint("10.0.1.1", "order-0", true))
+	slice.Namespace = "shop"
+	d := New(fake.NewSimpleClientset(slice), metav1.NamespaceDefault)
+
+	instances, err := d.GetService(context.Background(), "order.shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := summary(instances), map[string][]string{"order-0": {"grpc://10.0.1.1:9000"}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("GetService() = %v, want %v", got, want)
+	}
+	if instances, _ := d.GetService(context.Background(), "order"); len(instances) != 0 {
+		t.Fatalf("GetService() in default namespace = %v, want none", summary(instances))
+	}
+}
+
+func TestWatch(t *testing.T) {
+	ctx := context.Background()
+	grpcPort := []discoveryv1.EndpointPort{port("grpc", 9000)}
+	clientset := fake.NewSimpleClientset(endpointSlice("order-a", "order", grpcPort, endpoint("10.0.0.1", "order-0", true)))
+	slices := clientset.DiscoveryV1().EndpointSlices(metav1.NamespaceDefault)
+
+	w, err := New(clientset, metav1.NamespaceDefault).Watch(ctx, "order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	next := func(want map[string][]string) {
+		t.Helper()
+		done := make(chan struct{})
+		var got map[string][]string
+		go func() {
+			defer close(done)
+			instances, err := w.Next()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got = summary(instances)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Next() timed out, want %v", want)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Next() = %v, want %v", got, want)
+		}
+	}
+
+	// 首次调用立即返回当前实例
+	next(map[string][]string{"order-0": {"grpc://10.0.0.1:9000"}})
+
+	// 新增 EndpointSlice
+	if _, err := slices.Create(ctx, endpointSlice("order-b", "order", grpcPort, endpoint("10.0.0.2", "order-1", true)), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	next(map[string][]string{"order-0": {"grpc://10.0.0.1:9000"}, "order-1": {"grpc://10.0.0.2:9000"}})
+
+	// 实例变为未就绪
+	if _, err := slices.Update(ctx, endpointSlice("order-a", "order", grpcPort, endpoint("10.0.0.1", "order-0", false)), metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	next(map[string][]string{"order-1": {"grpc://10.0.0.2:9000"}})
+
+	// 其他服务的变化不会触发
+	if _, err := slices.Create(ctx, endpointSlice("user-a", "user", grpcPort, endpoint("10.0.0.3", "user-0", true)), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 删除 EndpointSlice
+	if err := slices.Delete(ctx, "order-b", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	next(map[string][]string{})
+
+	if err := w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Next(); err == nil {
+		t.Fatal("Next() after Stop should return an error")
+	}
+}
+
+func TestWatchCacheSyncTimeout(t *testing.T) {
+	timeout := cacheSyncTimeout
+	cacheSyncTimeout = 100 * time.Millisecond
+	defer func() { cacheSyncTimeout = timeout }()
+
+	// List 一直失败时缓存无法同步，Watch 超时返回错误而不是一直阻塞
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("list", "endpointslices", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+	if _, err := New(clientset, metav1.NamespaceDefault).Watch(context.Background(), "order"); err == nil {
+		t.Fatal("Watch() should fail when the cache cannot sync")
+	}
+}
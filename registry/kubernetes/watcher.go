@@ -0,0 +1,108 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+var _ registry.Watcher = (*watcher)(nil)
+
+// cacheSyncTimeout 等待 EndpointSlice 缓存首次同步的最长时间，避免 API Server 不可用或权限不足时 Watch 一直阻塞
+var cacheSyncTimeout = 10 * time.Second
+
+// watcher 通过 informer 监听服务的 EndpointSlice，实例变化时返回
+type watcher struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	serviceName string
+	lister      discoverylisters.EndpointSliceNamespaceLister
+	selector    labels.Selector
+	changed     chan struct{}
+	last        []*registry.ServiceInstance
+	first       bool
+}
+
+func newWatcher(ctx context.Context, clientset kubernetes.Interface, serviceName, name, namespace string) (*watcher, error) {
+	w := &watcher{
+		serviceName: serviceName,
+		selector:    labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: name}),
+		changed:     make(chan struct{}, 1),
+		first:       true,
+	}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = w.selector.String()
+		}),
+	)
+	informer := factory.Discovery().V1().EndpointSlices()
+	if _, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { w.notify() },
+		UpdateFunc: func(interface{}, interface{}) { w.notify() },
+		DeleteFunc: func(interface{}) { w.notify() },
+	}); err != nil {
+		w.cancel()
+		return nil, err
+	}
+	w.lister = informer.Lister().EndpointSlices(namespace)
+
+	factory.Start(w.ctx.Done())
+	syncCtx, cancel := context.WithTimeout(w.ctx, cacheSyncTimeout)
+	defer cancel()
+	for _, synced := range factory.WaitForCacheSync(syncCtx.Done()) {
+		if !synced {
+			w.cancel()
+			return nil, fmt.Errorf("timed out waiting for kubernetes EndpointSlice cache of %s", serviceName)
+		}
+	}
+	return w, nil
+}
+
+func (w *watcher) notify() {
+	select {
+	case w.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Next 返回变化后的实例列表，首次调用立即返回
+func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
+	for {
+		if !w.first {
+			select {
+			case <-w.ctx.Done():
+				return nil, w.ctx.Err()
+			case <-w.changed:
+			}
+		}
+		slices, err := w.lister.List(w.selector)
+		if err != nil {
+			return nil, err
+		}
+		items := instances(w.serviceName, slices)
+		if w.first || !reflect.DeepEqual(items, w.last) {
+			w.first = false
+			w.last = items
+			return items, nil
+		}
+	}
+}
+
+// Stop 停止监听
+func (w *watcher) Stop() error {
+	w.cancel()
+	return nil
+}
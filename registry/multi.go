@@ -0,0 +1,315 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/addls/go-boot/config"
+	"github.com/go-kratos/kratos/v2/registry"
+)
+
+// watchRetryInterval 单个注册中心监听出错后的重试间隔
+const watchRetryInterval = time.Second
+
+// initialWatchTimeout 首次 Next 等待所有注册中心返回初始实例的最长时间
+// 超时后先返回已收到的实例，避免某个注册中心不可用时调用方一直阻塞
+var initialWatchTimeout = 5 * time.Second
+
+var (
+	_ registry.Registrar = (*multiRegistry)(nil)
+	_ registry.Discovery = (*multiRegistry)(nil)
+)
+
+// multiRegistry 组合多个注册中心，用于在注册中心之间迁移
+// 服务同时注册到所有开启注册的注册中心，服务发现合并各注册中心的实例并按 endpoint 去重
+type multiRegistry struct {
+	registries []*Registry
+}
+
+// newMulti 根据 registries 配置创建组合注册中心，没有可用的注册中心时返回 nil
+func newMulti(cfgs []*config.Discovery) (*multiRegistry, error) {
+	m := &multiRegistry{}
+	for i, cfg := range cfgs {
+		r, err := New(cfg)
+		if err != nil {
+			_ = m.Close()
+			return nil, fmt.Errorf("registries[%d] (%s): %w", i, cfg.Type, err)
+		}
+		if r != nil {
+			m.registries = append(m.registries, r)
+		}
+	}
+	if len(m.registries) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
+
+// register 是否有开启服务注册的注册中心
+func (m *multiRegistry) register() bool {
+	for _, r := range m.registries {
+		if r.Registrar() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// registered 检查服务实例是否仍在每个开启注册的注册中心中
+func (m *multiRegistry) registered(ctx context.Context, ins *registry.ServiceInstance) bool {
+	for _, r := range m.registries {
+		if r.Registrar() != nil && !r.Registered(ctx, ins) {
+			return false
+		}
+	}
+	return true
+}
+
+// Register 注册到所有开启注册的注册中心，任一失败时撤销已成功的注册并返回错误，由调用方整体重试
+func (m *multiRegistry) Register(ctx context.Context, ins *registry.ServiceInstance) error {
+	var registered []registry.Registrar
+	for _, r := range m.registries {
+		registrar := r.Registrar()
+		if registrar == nil {
+			continue
+		}
+		if err := registrar.Register(ctx, ins); err != nil {
+			for _, done := range registered {
+				_ = done.Deregister(ctx, ins)
+			}
+			return err
+		}
+		registered = append(registered, registrar)
+	}
+	return nil
+}
+
+// Deregister 从所有开启注册的注册中心注销
+func (m *multiRegistry) Deregister(ctx context.Context, ins *registry.ServiceInstance) error {
+	var errs []error
+	for _, r := range m.registries {
+		if registrar := r.Registrar(); registrar != nil {
+			errs = append(errs, registrar.Deregister(ctx, ins))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetService 合并各注册中心的服务实例，所有注册中心都查询失败时返回错误
+func (m *multiRegistry) GetService(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	var (
+		results [][]*registry.ServiceInstance
+		errs    []error
+	)
+	for _, r := range m.registries {
+		instances, err := r.GetService(ctx, serviceName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results = append(results, instances)
+	}
+	if len(results) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return merge(results), nil
+}
+
+// Services 合并各注册中心的服务名称，所有注册中心都查询失败时返回错误
+func (m *multiRegistry) Services(ctx context.Context) ([]string, error) {
+	var (
+		services []string
+		seen     = make(map[string]bool)
+		errs     []error
+		ok       bool
+	)
+	for _, r := range m.registries {
+		names, err := r.Services(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ok = true
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				services = append(services, name)
+			}
+		}
+	}
+	if !ok {
+		return nil, errors.Join(errs...)
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// Watch 监听所有注册中心的服务实例变化
+func (m *multiRegistry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &multiWatcher{
+		ctx:      ctx,
+		cancel:   cancel,
+		updates:  make(chan struct{}, 1),
+		initial:  make(chan struct{}),
+		latest:   make([][]*registry.ServiceInstance, len(m.registries)),
+		received: make([]bool, len(m.registries)),
+		pending:  len(m.registries),
+	}
+	for _, r := range m.registries {
+		watcher, err := r.Watch(ctx, serviceName)
+		if err != nil {
+			_ = w.Stop()
+			return nil, err
+		}
+		w.watchers = append(w.watchers, watcher)
+	}
+	w.timer = time.AfterFunc(initialWatchTimeout, w.initialized)
+	for i, watcher := range w.watchers {
+		go w.run(i, watcher)
+	}
+	return w, nil
+}
+
+// Health 检查所有注册中心的连接
+func (m *multiRegistry) Health(ctx context.Context) error {
+	var errs []error
+	for _, r := range m.registries {
+		errs = append(errs, r.Health(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Close 关闭所有注册中心客户端
+func (m *multiRegistry) Close() error {
+	var errs []error
+	for _, r := range m.registries {
+		errs = append(errs, r.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// multiWatcher 合并多个注册中心的 Watcher，任一注册中心的实例变化时返回合并后的全部实例
+// 首次 Next 等待所有注册中心返回初始实例（最长 initialWatchTimeout），避免先返回部分实例
+type multiWatcher struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	watchers []registry.Watcher
+	updates  chan struct{}
+	initial  chan struct{}
+	once     sync.Once
+	timer    *time.Timer
+	started  bool // 只由调用 Next 的 goroutine 访问
+
+	mu       sync.Mutex
+	latest   [][]*registry.ServiceInstance
+	received []bool
+	pending  int
+}
+
+// initialized 所有注册中心返回初始实例或等待超时
+func (w *multiWatcher) initialized() {
+	w.once.Do(func() { close(w.initial) })
+}
+
+// run 持续读取单个注册中心的实例变化
+func (w *multiWatcher) run(i int, watcher registry.Watcher) {
+	for {
+		instances, err := watcher.Next()
+		if err != nil {
+			if w.ctx.Err() != nil {
+				return
+			}
+			// 单个注册中心出错时保留其最近一次的实例，稍后继续监听
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(watchRetryInterval):
+			}
+			continue
+		}
+		w.mu.Lock()
+		w.latest[i] = instances
+		if !w.received[i] {
+			w.received[i] = true
+			if w.pending--; w.pending == 0 {
+				w.initialized()
+			}
+		}
+		w.mu.Unlock()
+		select {
+		case w.updates <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Next 等待任一注册中心的实例变化，返回合并后的全部实例
+func (w *multiWatcher) Next() ([]*registry.ServiceInstance, error) {
+	if !w.started {
+		w.started = true
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case <-w.initial:
+		}
+		// 等待期间的变化已包含在本次结果中
+		select {
+		case <-w.updates:
+		default:
+		}
+	} else {
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case <-w.updates:
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return merge(w.latest), nil
+}
+
+// Stop 停止监听
+func (w *multiWatcher) Stop() error {
+	w.cancel()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	var errs []error
+	for _, watcher := range w.watchers {
+		errs = append(errs, watcher.Stop())
+	}
+	return errors.Join(errs...)
+}
+
+// merge 合并多个注册中心的实例，按 endpoint 去重，先出现的实例优先
+func merge(results [][]*registry.ServiceInstance) []*registry.ServiceInstance {
+	var (
+		merged []*registry.ServiceInstance
+		seen   = make(map[string]bool)
+	)
+	for _, instances := range results {
+		for _, ins := range instances {
+			duplicate := false
+			for _, endpoint := range ins.Endpoints {
+				if seen[endpoint] {
+					duplicate = true
+					break
+				}
+			}
+			if duplicate {
+				continue
+			}
+			for _, endpoint := range ins.Endpoints {
+				seen[endpoint] = true
+			}
+			merged = append(merged, ins)
+		}
+	}
+	return merged
+}
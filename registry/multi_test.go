@@ -0,0 +1,293 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/addls/go-boot/config"
+	"github.com/addls/go-boot/registry/static"
+	"github.com/go-kratos/kratos/v2/registry"
+)
+
+func instance(id string, endpoints ...string) *registry.ServiceInstance {
+	return &registry.ServiceInstance{ID: id, Name: "order", Endpoints: endpoints}
+}
+
+func ids(instances []*registry.ServiceInstance) []string {
+	out := make([]string, 0, len(instances))
+	for _, ins := range instances {
+		out = append(out, ins.ID)
+	}
+	return out
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		results [][]*registry.ServiceInstance
+		want    []string
+	}{
+		{name: "empty", want: []string{}},
+		{
+			name:    "disjoint",
+			results: [][]*registry.ServiceInstance{{instance("a", "grpc://1")}, {instance("b", "grpc://2")}},
+			want:    []string{"a", "b"},
+		},
+		{
+			name:    "same endpoint keeps the first registry",
+			results: [][]*registry.ServiceInstance{{instance("consul-a", "grpc://1")}, {instance("etcd-a", "grpc://1")}},
+			want:    []string{"consul-a"},
+		},
+		{
+			name: "any shared endpoint is a duplicate",
+			results: [][]*registry.ServiceInstance{
+				{instance("consul-a", "grpc://1", "http://1")},
+				{instance("etcd-a", "http://1"), instance("etcd-b", "grpc://2", "http://2")},
+			},
+			want: []string{"consul-a", "etcd-b"},
+		},
+		{
+			name:    "duplicates in one registry",
+			results: [][]*registry.ServiceInstance{{instance("a", "grpc://1"), instance("a2", "grpc://1")}},
+			want:    []string{"a"},
+		},
+		{
+			name:    "failed registry is skipped",
+			results: [][]*registry.ServiceInstance{nil, {instance("b", "grpc://2")}},
+			want:    []string{"b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(merge(tt.results)); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("merge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiDiscovery(t *testing.T) {
+	r, err := New(&config.Discovery{Registries: []*config.Discovery{
+		{Type: "static", Services: map[string][]string{"order": {"grpc://10.0.0.1:9000"}, "user": {"grpc://10.0.0.3:9000"}}},
+		{Type: "static", Services: map[string][]string{"order": {"grpc://10.0.0.1:9000", "grpc://10.0.0.2:9000"}, "payment": {"grpc://10.0.0.4:9000"}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	instances, err := r.GetService(ctx, "order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(instances), []string{"grpc://10.0.0.1:9000", "grpc://10.0.0.2:9000"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("GetService() = %v, want %v", got, want)
+	}
+	services, err := r.Services(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"order", "payment", "user"}; !reflect.DeepEqual(services, want) {
+		t.Fatalf("Services() = %v, want %v", services, want)
+	}
+	if r.Registrar() != nil {
+		t.Fatal("static registries do not register")
+	}
+}
+
+func TestMultiWatch(t *testing.T) {
+	first := static.New(map[string][]string{"order": {"grpc://10.0.0.1:9000"}})
+	second := static.New(nil)
+	m := &multiRegistry{registries: []*Registry{{backend: first}, {backend: second}}}
+
+	w, err := m.Watch(context.Background(), "order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// 等待合并结果达到预期，一次实例变化可能触发多次返回
+	next := func(want []string) {
+		t.Helper()
+		for {
+			instances, err := w.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(instances); reflect.DeepEqual(got, want) {
+				return
+			}
+		}
+	}
+	next([]string{"grpc://10.0.0.1:9000"})
+
+	second.Update(map[string][]string{"order": {"grpc://10.0.0.1:9000", "grpc://10.0.0.2:9000"}})
+	next([]string{"grpc://10.0.0.1:9000", "grpc://10.0.0.2:9000"})
+
+	first.Update(nil)
+	next([]string{"grpc://10.0.0.1:9000", "grpc://10.0.0.2:9000"})
+	if err := w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Next(); err == nil {
+		t.Fatal("Next() after Stop should return an error")
+	}
+}
+
+// blockingDiscovery Watcher 在 release 关闭前不返回实例的注册中心，模拟首次结果较慢或不可用的注册中心
+type blockingDiscovery struct {
+	release   chan struct{}
+	instances []*registry.ServiceInstance
+}
+
+func (d *blockingDiscovery) GetService(context.Context, string) ([]*registry.ServiceInstance, error) {
+	return d.instances, nil
+}
+
+func (d *blockingDiscovery) Watch(ctx context.Context, _ string) (registry.Watcher, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &blockingWatcher{ctx: ctx, cancel: cancel, d: d}, nil
+}
+
+type blockingWatcher struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	d      *blockingDiscovery
+	done   bool
+}
+
+func (w *blockingWatcher) Next() ([]*registry.ServiceInstance, error) {
+	if !w.done {
+		select {
+		case <-w.d.release:
+			w.done = true
+			return w.d.instances, nil
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		}
+	}
+	<-w.ctx.Done()
+	return nil, w.ctx.Err()
+}
+
+func (w *blockingWatcher) Stop() error {
+	w.cancel()
+	return nil
+}
+
+func TestMultiWatchInitial(t *testing.T) {
+	timeout := initialWatchTimeout
+	defer func() { initialWatchTimeout = timeout }()
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		release time.Duration // 0 表示一直不返回
+		want    []string
+	}{
+		{name: "waits for every registry", timeout: 5 * time.Second, release: 50 * time.Millisecond, want: []string{"grpc://10.0.0.1:9000", "grpc://10.0.0.2:9000"}},
+		{name: "returns partial result after timeout", timeout: 50 * time.Millisecond, want: []string{"grpc://10.0.0.1:9000"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initialWatchTimeout = tt.timeout
+			slow := &blockingDiscovery{release: make(chan struct{}), instances: []*registry.ServiceInstance{instance("grpc://10.0.0.2:9000", "grpc://10.0.0.2:9000")}}
+			if tt.release > 0 {
+				time.AfterFunc(tt.release, func() { close(slow.release) })
+			}
+			m := &multiRegistry{registries: []*Registry{
+				{backend: static.New(map[string][]string{"order": {"grpc://10.0.0.1:9000"}})},
+				{backend: slow},
+			}}
+			w, err := m.Watch(context.Background(), "order")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Stop()
+
+			start := time.Now()
+			instances, err := w.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(instances); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("first Next() = %v, want %v", got, tt.want)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("first Next() took %s", elapsed)
+			}
+		})
+	}
+}
+
+// fakeRegistrar 记录注册状态的注册中心，fail 为 true 时注册失败
+type fakeRegistrar struct {
+	*static.Discovery
+	fail bool
+
+	mu         sync.Mutex
+	registered map[string]bool
+}
+
+func newFakeRegistrar(fail bool) *fakeRegistrar {
+	return &fakeRegistrar{Discovery: static.New(nil), fail: fail, registered: make(map[string]bool)}
+}
+
+func (f *fakeRegistrar) Register(_ context.Context, ins *registry.ServiceInstance) error {
+	if f.fail {
+		return errors.New("register failed")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.registered[ins.ID] = true
+	return nil
+}
+
+func (f *fakeRegistrar) Deregister(_ context.Context, ins *registry.ServiceInstance) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.registered, ins.ID)
+	return nil
+}
+
+func TestMultiRegister(t *testing.T) {
+	tests := []struct {
+		name       string
+		secondFail bool
+		wantErr    bool
+		want       bool // 第一个注册中心中是否仍有实例
+	}{
+		{name: "all succeed", want: true},
+		{name: "roll back on failure", secondFail: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := newFakeRegistrar(false), newFakeRegistrar(tt.secondFail)
+			discoveryOnly := newFakeRegistrar(true)
+			m := &multiRegistry{registries: []*Registry{
+				{backend: first, register: true},
+				{backend: discoveryOnly},
+				{backend: second, register: true},
+			}}
+			ins := instance("order-1", "grpc://10.0.0.1:9000")
+			if err := m.Register(context.Background(), ins); (err != nil) != tt.wantErr {
+				t.Fatalf("Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if first.registered["order-1"] != tt.want {
+				t.Fatalf("first registered = %v, want %v", first.registered["order-1"], tt.want)
+			}
+			if !tt.wantErr {
+				if err := m.Deregister(context.Background(), ins); err != nil {
+					t.Fatal(err)
+				}
+				if first.registered["order-1"] || second.registered["order-1"] {
+					t.Fatal("instance should be deregistered from all registries")
+				}
+			}
+		})
+	}
+}
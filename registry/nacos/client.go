@@ -0,0 +1,159 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultContextPath nacos 默认访问路径
+const defaultContextPath = "/nacos"
+
+// client nacos Open API 客户端
+// 多个地址时按顺序故障转移，配置用户名后自动登录并在 token 过期前刷新
+type client struct {
+	servers    []string
+	httpClient *http.Client
+	username   string
+	password   string
+
+	mu      sync.Mutex
+	current int       // 当前使用的地址下标
+	token   string    // accessToken
+	expire  time.Time // token 过期时间
+}
+
+func newClient(endpoints []string, timeout time.Duration, username, password string) *client {
+	servers := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "http://" + endpoint
+		}
+		endpoint = strings.TrimRight(endpoint, "/")
+		if u, err := url.Parse(endpoint); err == nil && u.Path == "" {
+			endpoint += defaultContextPath
+		}
+		servers = append(servers, endpoint)
+	}
+	return &client{
+		servers:    servers,
+		httpClient: &http.Client{Timeout: timeout},
+		username:   username,
+		password:   password,
+	}
+}
+
+// do 调用 nacos Open API，out 为 nil 时忽略响应体
+func (c *client) do(ctx context.Context, method, path string, params url.Values, out interface{}) error {
+	if c.username != "" {
+		token, err := c.accessToken(ctx)
+		if err != nil {
+			return err
+		}
+		params.Set("accessToken", token)
+	}
+	body, err := c.request(ctx, method, path, params)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("nacos %s %s: decode response failed: %w", method, path, err)
+	}
+	return nil
+}
+
+// request 依次尝试各个地址，网络错误或 5xx 时切换到下一个地址
+func (c *client) request(ctx context.Context, method, path string, params url.Values) ([]byte, error) {
+	c.mu.Lock()
+	start := c.current
+	c.mu.Unlock()
+
+	var lastErr error
+	for i := range c.servers {
+		idx := (start + i) % len(c.servers)
+		body, status, err := c.send(ctx, method, c.servers[idx]+path, params)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		// 5xx 通常是该节点异常（如正在重启、选主），其他节点可能正常
+		if status >= 500 {
+			lastErr = fmt.Errorf("%s: status %d: %s", c.servers[idx], status, strings.TrimSpace(string(body)))
+			continue
+		}
+		c.mu.Lock()
+		c.current = idx
+		c.mu.Unlock()
+		if status < 200 || status > 299 {
+			return nil, fmt.Errorf("nacos %s %s: status %d: %s", method, path, status, strings.TrimSpace(string(body)))
+		}
+		return body, nil
+	}
+	return nil, fmt.Errorf("nacos %s %s: all servers unavailable: %w", method, path, lastErr)
+}
+
+func (c *client) send(ctx context.Context, method, rawURL string, params url.Values) ([]byte, int, error) {
+	var (
+		req *http.Request
+		err error
+	)
+	if method == http.MethodGet || method == http.MethodDelete {
+		req, err = http.NewRequestWithContext(ctx, method, rawURL+"?"+params.Encode(), nil)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, method, rawURL, strings.NewReader(params.Encode()))
+		if req != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return body, resp.StatusCode, err
+}
+
+// accessToken 获取 accessToken，过期前自动重新登录
+func (c *client) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	token, expire := c.token, c.expire
+	c.mu.Unlock()
+	if token != "" && time.Now().Before(expire) {
+		return token, nil
+	}
+
+	body, err := c.request(ctx, http.MethodPost, "/v1/auth/login", url.Values{
+		"username": {c.username},
+		"password": {c.password},
+	})
+	if err != nil {
+		return "", fmt.Errorf("nacos login failed: %w", err)
+	}
+	var result struct {
+		AccessToken string `json:"accessToken"`
+		TokenTTL    int64  `json:"tokenTtl"` // 秒
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("nacos login failed: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = result.AccessToken
+	// 提前 10% 刷新
+	c.expire = time.Now().Add(time.Duration(result.TokenTTL) * time.Second * 9 / 10)
+	return c.token, nil
+}
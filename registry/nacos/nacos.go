@@ -0,0 +1,430 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/addls/go-boot/config"
+	"github.com/go-kratos/kratos/v2/registry"
+)
+
+// nacos 默认配置
+const (
+	defaultGroup        = "DEFAULT_GROUP"
+	defaultCluster      = "DEFAULT"
+	defaultWeight       = 100
+	defaultBeatInterval = 5 * time.Second
+	defaultPollInterval = 5 * time.Second
+
+	// codeResourceNotFound 心跳响应码：实例不存在（已被服务端剔除），需要重新注册
+	codeResourceNotFound = 20404
+)
+
+var (
+	_ registry.Registrar = (*Registry)(nil)
+	_ registry.Discovery = (*Registry)(nil)
+)
+
+// Registry nacos 注册中心，同时实现 Registrar 和 Discovery 接口
+// 基于 nacos Open API，每个 endpoint 注册为同名服务下的一个实例，metadata 中的 kind 记录协议（grpc、http）
+type Registry struct {
+	client       *client
+	namespace    string
+	group        string
+	cluster      string
+	clusters     []string
+	weight       float64
+	ephemeral    bool
+	beatInterval time.Duration
+	pollInterval time.Duration
+
+	mu    sync.Mutex
+	beats map[string]context.CancelFunc // 临时实例的心跳，key 为 服务名/ip:port
+}
+
+// NewRegistrar 创建 nacos 注册中心
+func NewRegistrar(cfg *config.Discovery) (registry.Registrar, error) {
+	return New(cfg)
+}
+
+// NewDiscovery 创建 nacos 服务发现客户端
+func NewDiscovery(cfg *config.Discovery) (registry.Discovery, error) {
+	// nacos Registry 同时实现了 Registrar 和 Discovery 接口
+	return New(cfg)
+}
+
+// New 创建 nacos Registry
+func New(cfg *config.Discovery) (*Registry, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, fmt.Errorf("nacos endpoints cannot be empty")
+	}
+
+	// 解析超时时间
+	timeout := 5 * time.Second
+	if cfg.Timeout != "" {
+		duration, err := time.ParseDuration(cfg.Timeout)
+		if err == nil {
+			timeout = duration
+		}
+	}
+
+	// 解析实例列表轮询间隔
+	pollInterval := defaultPollInterval
+	if cfg.PollInterval != "" {
+		duration, err := time.ParseDuration(cfg.PollInterval)
+		if err == nil && duration > 0 {
+			pollInterval = duration
+		}
+	}
+
+	r := &Registry{
+		client:       newClient(cfg.Endpoints, timeout, cfg.Username, cfg.Password),
+		namespace:    cfg.Namespace,
+		group:        cfg.Group,
+		cluster:      cfg.Cluster,
+		clusters:     cfg.Clusters,
+		weight:       cfg.Weight,
+		ephemeral:    cfg.Ephemeral == nil || *cfg.Ephemeral,
+		beatInterval: defaultBeatInterval,
+		pollInterval: pollInterval,
+		beats:        make(map[string]context.CancelFunc),
+	}
+	if r.group == "" {
+		r.group = defaultGroup
+	}
+	if r.cluster == "" {
+		r.cluster = defaultCluster
+	}
+	if r.weight <= 0 {
+		r.weight = defaultWeight
+	}
+	return r, nil
+}
+
+// instance nacos 实例
+type instance struct {
+	ip          string
+	port        int
+	serviceName string
+	weight      float64
+	metadata    map[string]string
+}
+
+// instances 将服务实例按 endpoint 拆分为 nacos 实例
+func (r *Registry) instances(si *registry.ServiceInstance) ([]*instance, error) {
+	if si.Name == "" {
+		return nil, fmt.Errorf("nacos: service instance name cannot be empty")
+	}
+	items := make([]*instance, 0, len(si.Endpoints))
+	for _, endpoint := range si.Endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		host, port, err := net.SplitHostPort(u.Host)
+		if err != nil {
+			return nil, err
+		}
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, err
+		}
+		md := make(map[string]string, len(si.Metadata)+3)
+		for k, v := range si.Metadata {
+			md[k] = v
+		}
+		md["kind"] = u.Scheme
+		md["version"] = si.Version
+		md["id"] = si.ID
+		// 实例元数据中的 weight 优先
+		weight := r.weight
+		if w, err := strconv.ParseFloat(si.Metadata["weight"], 64); err == nil && w > 0 {
+			weight = w
+		}
+		items = append(items, &instance{ip: host, port: p, serviceName: si.Name, weight: weight, metadata: md})
+	}
+	return items, nil
+}
+
+func (r *Registry) params(ins *instance) url.Values {
+	params := url.Values{
+		"serviceName": {ins.serviceName},
+		"groupName":   {r.group},
+		"clusterName": {r.cluster},
+		"ip":          {ins.ip},
+		"port":        {strconv.Itoa(ins.port)},
+		"ephemeral":   {strconv.FormatBool(r.ephemeral)},
+	}
+	if r.namespace != "" {
+		params.Set("namespaceId", r.namespace)
+	}
+	return params
+}
+
+// Register 注册服务实例，临时实例注册后定期发送心跳
+// 任一 endpoint 注册失败时注销已注册的 endpoint 并停止其心跳，避免留下不完整的实例
+func (r *Registry) Register(ctx context.Context, si *registry.ServiceInstance) error {
+	items, err := r.instances(si)
+	if err != nil {
+		return err
+	}
+	for i, ins := range items {
+		if err := r.register(ctx, ins); err != nil {
+			// ctx 可能已超时，回滚不受其影响
+			rctx := context.WithoutCancel(ctx)
+			for _, done := range items[:i] {
+				_ = r.deregister(rctx, done)
+			}
+			return err
+		}
+		if r.ephemeral {
+			r.startBeat(ins)
+		}
+	}
+	return nil
+}
+
+func (r *Registry) register(ctx context.Context, ins *instance) error {
+	md, err := json.Marshal(ins.metadata)
+	if err != nil {
+		return err
+	}
+	params := r.params(ins)
+	params.Set("weight", strconv.FormatFloat(ins.weight, 'f', -1, 64))
+	params.Set("enabled", "true")
+	params.Set("healthy", "true")
+	params.Set("metadata", string(md))
+	if err := r.client.do(ctx, http.MethodPost, "/v1/ns/instance", params, nil); err != nil {
+		return fmt.Errorf("nacos register %s %s:%d failed: %w", ins.serviceName, ins.ip, ins.port, err)
+	}
+	return nil
+}
+
+// Deregister 注销服务实例并停止心跳
+func (r *Registry) Deregister(ctx context.Context, si *registry.ServiceInstance) error {
+	items, err := r.instances(si)
+	if err != nil {
+		return err
+	}
+	for _, ins := range items {
+		if err := r.deregister(ctx, ins); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deregister 停止心跳并注销单个 nacos 实例
+func (r *Registry) deregister(ctx context.Context, ins *instance) error {
+	r.stopBeat(ins)
+	if err := r.client.do(ctx, http.MethodDelete, "/v1/ns/instance", r.params(ins), nil); err != nil {
+		return fmt.Errorf("nacos deregister %s %s:%d failed: %w", ins.serviceName, ins.ip, ins.port, err)
+	}
+	return nil
+}
+
+func beatKey(ins *instance) string {
+	return ins.serviceName + "/" + net.JoinHostPort(ins.ip, strconv.Itoa(ins.port))
+}
+
+// startBeat 启动临时实例心跳，实例被服务端剔除时重新注册
+func (r *Registry) startBeat(ins *instance) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	if stop, ok := r.beats[beatKey(ins)]; ok {
+		stop()
+	}
+	r.beats[beatKey(ins)] = cancel
+	r.mu.Unlock()
+
+	go func() {
+		interval := r.beatInterval
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+			next, err := r.beat(ctx, ins)
+			if err != nil {
+				continue
+			}
+			if next > 0 {
+				interval = next
+			}
+		}
+	}()
+}
+
+func (r *Registry) stopBeat(ins *instance) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stop, ok := r.beats[beatKey(ins)]; ok {
+		stop()
+		delete(r.beats, beatKey(ins))
+	}
+}
+
+// beat 发送一次心跳，返回服务端要求的心跳间隔
+func (r *Registry) beat(ctx context.Context, ins *instance) (time.Duration, error) {
+	beat, err := json.Marshal(map[string]interface{}{
+		"serviceName": r.group + "@@" + ins.serviceName,
+		"cluster":     r.cluster,
+		"ip":          ins.ip,
+		"port":        ins.port,
+		"weight":      ins.weight,
+		"metadata":    ins.metadata,
+		"scheduled":   true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	params := url.Values{
+		"serviceName": {ins.serviceName},
+		"groupName":   {r.group},
+		"ephemeral":   {"true"},
+		"beat":        {string(beat)},
+	}
+	if r.namespace != "" {
+		params.Set("namespaceId", r.namespace)
+	}
+	var result struct {
+		ClientBeatInterval int64 `json:"clientBeatInterval"` // 毫秒
+		Code               int   `json:"code"`
+	}
+	if err := r.client.do(ctx, http.MethodPut, "/v1/ns/instance/beat", params, &result); err != nil {
+		return 0, err
+	}
+	if result.Code == codeResourceNotFound {
+		if err := r.register(ctx, ins); err != nil {
+			return 0, err
+		}
+	}
+	return time.Duration(result.ClientBeatInterval) * time.Millisecond, nil
+}
+
+// GetService 获取服务的健康实例，默认返回所有集群的实例，配置 clusters 时只返回这些集群的实例
+func (r *Registry) GetService(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	params := url.Values{
+		"serviceName": {serviceName},
+		"groupName":   {r.group},
+		"healthyOnly": {"true"},
+	}
+	if len(r.clusters) > 0 {
+		params.Set("clusters", strings.Join(r.clusters, ","))
+	}
+	if r.namespace != "" {
+		params.Set("namespaceId", r.namespace)
+	}
+	var result struct {
+		Hosts []struct {
+			InstanceID string            `json:"instanceId"`
+			IP         string            `json:"ip"`
+			Port       int               `json:"port"`
+			Weight     float64           `json:"weight"`
+			Healthy    bool              `json:"healthy"`
+			Enabled    bool              `json:"enabled"`
+			Metadata   map[string]string `json:"metadata"`
+		} `json:"hosts"`
+	}
+	if err := r.client.do(ctx, http.MethodGet, "/v1/ns/instance/list", params, &result); err != nil {
+		return nil, err
+	}
+
+	// 同一服务实例的多个 endpoint 合并为一个 ServiceInstance
+	items := make([]*registry.ServiceInstance, 0, len(result.Hosts))
+	byID := make(map[string]*registry.ServiceInstance, len(result.Hosts))
+	for _, host := range result.Hosts {
+		if !host.Healthy || !host.Enabled {
+			continue
+		}
+		md := host.Metadata
+		if md == nil {
+			md = make(map[string]string)
+		}
+		kind := md["kind"]
+		if kind == "" {
+			kind = "grpc"
+		}
+		id := md["id"]
+		if id == "" {
+			id = host.InstanceID
+		}
+		endpoint := kind + "://" + net.JoinHostPort(host.IP, strconv.Itoa(host.Port))
+		if ins, ok := byID[id]; ok {
+			ins.Endpoints = append(ins.Endpoints, endpoint)
+			continue
+		}
+		md["weight"] = strconv.FormatFloat(host.Weight, 'f', -1, 64)
+		delete(md, "kind")
+		ins := &registry.ServiceInstance{
+			ID:        id,
+			Name:      serviceName,
+			Version:   md["version"],
+			Metadata:  md,
+			Endpoints: []string{endpoint},
+		}
+		byID[id] = ins
+		items = append(items, ins)
+	}
+	return items, nil
+}
+
+// Services 列出分组内的服务名称
+func (r *Registry) Services(ctx context.Context) ([]string, error) {
+	const pageSize = 100
+	var services []string
+	for page := 1; ; page++ {
+		params := url.Values{
+			"pageNo":    {strconv.Itoa(page)},
+			"pageSize":  {strconv.Itoa(pageSize)},
+			"groupName": {r.group},
+		}
+		if r.namespace != "" {
+			params.Set("namespaceId", r.namespace)
+		}
+		var result struct {
+			Count int      `json:"count"`
+			Doms  []string `json:"doms"`
+		}
+		if err := r.client.do(ctx, http.MethodGet, "/v1/ns/service/list", params, &result); err != nil {
+			return nil, err
+		}
+		services = append(services, result.Doms...)
+		if len(result.Doms) < pageSize || len(services) >= result.Count {
+			break
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// Health 检查与 nacos 的连接
+func (r *Registry) Health(ctx context.Context) error {
+	return r.client.do(ctx, http.MethodGet, "/v1/console/health/liveness", url.Values{}, nil)
+}
+
+// Close 停止所有心跳
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, stop := range r.beats {
+		stop()
+		delete(r.beats, key)
+	}
+	return nil
+}
+
+// Watch 监听服务实例变化
+func (r *Registry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	return newWatcher(ctx, r, serviceName), nil
+}
@@ -0,0 +1,368 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/addls/go-boot/config"
+	"github.com/go-kratos/kratos/v2/registry"
+)
+
+// fakeNacos 模拟 nacos Open API 的实例注册、心跳、查询与登录
+type fakeNacos struct {
+	token    string // 非空时校验 accessToken
+	failPort string // 注册该端口时返回 500
+
+	mu        sync.Mutex
+	instances map[string]map[string]string // ip:port -> 注册参数
+	beats     map[string]int               // ip:port -> 心跳次数
+	logins    int
+}
+
+func newFakeNacos(t *testing.T) (*fakeNacos, *httptest.Server) {
+	f := &fakeNacos{instances: make(map[string]map[string]string), beats: make(map[string]int)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeNacos) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/nacos/v1/auth/login" {
+		if r.Form.Get("username") != "nacos" || r.Form.Get("password") != "secret" {
+			http.Error(w, "unknown user", http.StatusForbidden)
+			return
+		}
+		f.logins++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"accessToken": f.token, "tokenTtl": 18000})
+		return
+	}
+	if f.token != "" && r.Form.Get("accessToken") != f.token {
+		http.Error(w, "token invalid", http.StatusForbidden)
+		return
+	}
+
+	key := r.Form.Get("ip") + ":" + r.Form.Get("port")
+	switch r.Method + " " + r.URL.Path {
+	case "POST /nacos/v1/ns/instance":
+		if r.Form.Get("port") == f.failPort {
+			http.Error(w, "server error", http.StatusInternalServerError)
+			return
+		}
+		params := make(map[string]string)
+		for k := range r.Form {
+			params[k] = r.Form.Get(k)
+		}
+		f.instances[key] = params
+		_, _ = w.Write([]byte("ok"))
+	case "DELETE /nacos/v1/ns/instance":
+		delete(f.instances, key)
+		_, _ = w.Write([]byte("ok"))
+	case "PUT /nacos/v1/ns/instance/beat":
+		var beat struct {
+			IP   string `json:"ip"`
+			Port int    `json:"port"`
+		}
+		_ = json.Unmarshal([]byte(r.Form.Get("beat")), &beat)
+		f.beats[beat.IP+":"+strconv.Itoa(beat.Port)]++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"clientBeatInterval": 10, "code": 10200})
+	case "GET /nacos/v1/ns/instance/list":
+		var (
+			hosts    []map[string]interface{}
+			clusters = r.Form.Get("clusters")
+		)
+		for _, params := range f.instances {
+			if params["serviceName"] != r.Form.Get("serviceName") {
+				continue
+			}
+			if clusters != "" && !slices.Contains(strings.Split(clusters, ","), params["clusterName"]) {
+				continue
+			}
+			var md map[string]string
+			_ = json.Unmarshal([]byte(params["metadata"]), &md)
+			port, _ := strconv.Atoi(params["port"])
+			hosts = append(hosts, map[string]interface{}{
+				"ip": params["ip"], "port": port, "weight": 100.0,
+				"healthy": true, "enabled": true, "metadata": md,
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"hosts": hosts})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeNacos) registered(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.instances[key]
+	return ok
+}
+
+func (f *fakeNacos) beatCount(key string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.beats[key]
+}
+
+func newTestRegistry(t *testing.T, cfg *config.Discovery) *Registry {
+	t.Helper()
+	r, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.beatInterval = 10 * time.Millisecond
+	t.Cleanup(func() { _ = r.Close() })
+	return r
+}
+
+func testInstance(endpoints ...string) *registry.ServiceInstance {
+	return &registry.ServiceInstance{
+		ID:        "order-1",
+		Name:      "order",
+		Version:   "v1.0.0",
+		Metadata:  map[string]string{"zone": "a"},
+		Endpoints: endpoints,
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before timeout")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRegisterHeartbeatListDeregister(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		cfg   func(addr string) *config.Discovery
+	}{
+		{
+			name: "anonymous",
+			cfg: func(addr string) *config.Discovery {
+				return &config.Discovery{Endpoints: []string{addr}}
+			},
+		},
+		{
+			name:  "token auth",
+			token: "token-1",
+			cfg: func(addr string) *config.Discovery {
+				return &config.Discovery{Endpoints: []string{addr}, Username: "nacos", Password: "secret"}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, srv := newFakeNacos(t)
+			f.token = tt.token
+			r := newTestRegistry(t, tt.cfg(srv.URL))
+			ctx := context.Background()
+
+			ins := testInstance("grpc://10.0.0.1:9000", "http://10.0.0.1:8000")
+			if err := r.Register(ctx, ins); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			if !f.registered("10.0.0.1:9000") || !f.registered("10.0.0.1:8000") {
+				t.Fatal("both endpoints should be registered")
+			}
+			waitFor(t, func() bool { return f.beatCount("10.0.0.1:9000") > 1 && f.beatCount("10.0.0.1:8000") > 1 })
+
+			got, err := r.GetService(ctx, "order")
+			if err != nil {
+				t.Fatalf("GetService() error = %v", err)
+			}
+			if len(got) != 1 || got[0].ID != "order-1" || got[0].Version != "v1.0.0" || len(got[0].Endpoints) != 2 || got[0].Metadata["zone"] != "a" {
+				t.Fatalf("GetService() = %+v", got)
+			}
+
+			if err := r.Deregister(ctx, ins); err != nil {
+				t.Fatalf("Deregister() error = %v", err)
+			}
+			if f.registered("10.0.0.1:9000") || f.registered("10.0.0.1:8000") {
+				t.Fatal("endpoints should be deregistered")
+			}
+			r.mu.Lock()
+			beats := len(r.beats)
+			r.mu.Unlock()
+			if beats != 0 {
+				t.Fatalf("%d heartbeats still running after Deregister", beats)
+			}
+			if tt.token != "" && f.logins != 1 {
+				t.Fatalf("logins = %d, want 1 (token cached)", f.logins)
+			}
+		})
+	}
+}
+
+func TestRegisterRollback(t *testing.T) {
+	f, srv := newFakeNacos(t)
+	f.failPort = "8000"
+	r := newTestRegistry(t, &config.Discovery{Endpoints: []string{srv.URL}})
+
+	err := r.Register(context.Background(), testInstance("grpc://10.0.0.1:9000", "http://10.0.0.1:8000"))
+	if err == nil {
+		t.Fatal("Register() should fail when an endpoint fails")
+	}
+	if f.registered("10.0.0.1:9000") {
+		t.Fatal("registered endpoint should be rolled back")
+	}
+	r.mu.Lock()
+	beats := len(r.beats)
+	r.mu.Unlock()
+	if beats != 0 {
+		t.Fatalf("%d heartbeats still running after failed Register", beats)
+	}
+}
+
+func TestFailover(t *testing.T) {
+	tests := []struct {
+		name string
+		bad  func(t *testing.T) string
+	}{
+		{
+			name: "5xx",
+			bad: func(t *testing.T) string {
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					http.Error(w, "not leader", http.StatusServiceUnavailable)
+				}))
+				t.Cleanup(srv.Close)
+				return srv.URL
+			},
+		},
+		{
+			name: "connection refused",
+			bad: func(t *testing.T) string {
+				srv := httptest.NewServer(http.NotFoundHandler())
+				srv.Close()
+				return srv.URL
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, srv := newFakeNacos(t)
+			r := newTestRegistry(t, &config.Discovery{Endpoints: []string{tt.bad(t), strings.TrimPrefix(srv.URL, "http://")}})
+
+			if err := r.Register(context.Background(), testInstance("grpc://10.0.0.2:9000")); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			if !f.registered("10.0.0.2:9000") {
+				t.Fatal("instance should be registered on the second endpoint")
+			}
+			// 之后的请求直接使用可用的地址
+			r.client.mu.Lock()
+			current := r.client.current
+			r.client.mu.Unlock()
+			if current != 1 {
+				t.Fatalf("current endpoint = %d, want 1", current)
+			}
+		})
+	}
+}
+
+func TestClientErrorIsNotRetried(t *testing.T) {
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid param", http.StatusBadRequest)
+	}))
+	defer bad.Close()
+	f, good := newFakeNacos(t)
+
+	r := newTestRegistry(t, &config.Discovery{Endpoints: []string{bad.URL, good.URL}})
+	if err := r.Register(context.Background(), testInstance("grpc://10.0.0.3:9000")); err == nil {
+		t.Fatal("4xx should be returned to the caller")
+	}
+	if f.registered("10.0.0.3:9000") {
+		t.Fatal("4xx should not fail over to the next endpoint")
+	}
+}
+
+func TestGetServiceClusters(t *testing.T) {
+	_, srv := newFakeNacos(t)
+	ctx := context.Background()
+	for i, cluster := range []string{"DEFAULT", "sh", "bj"} {
+		r := newTestRegistry(t, &config.Discovery{Endpoints: []string{srv.URL}, Cluster: cluster})
+		ins := testInstance("grpc://10.0.1." + strconv.Itoa(i+1) + ":9000")
+		ins.ID = cluster
+		if err := r.Register(ctx, ins); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		clusters []string
+		want     []string
+	}{
+		{name: "all clusters by default", want: []string{"DEFAULT", "bj", "sh"}},
+		{name: "single cluster", clusters: []string{"sh"}, want: []string{"sh"}},
+		{name: "multiple clusters", clusters: []string{"sh", "bj"}, want: []string{"bj", "sh"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRegistry(t, &config.Discovery{Endpoints: []string{srv.URL}, Clusters: tt.clusters})
+			instances, err := r.GetService(ctx, "order")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, ins := range instances {
+				got = append(got, ins.ID)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("GetService() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchPollInterval(t *testing.T) {
+	_, srv := newFakeNacos(t)
+	ctx := context.Background()
+	r := newTestRegistry(t, &config.Discovery{Endpoints: []string{srv.URL}, PollInterval: "10ms"})
+	if r.pollInterval != 10*time.Millisecond {
+		t.Fatalf("pollInterval = %s, want 10ms", r.pollInterval)
+	}
+
+	w, err := r.Watch(ctx, "order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+	if instances, err := w.Next(); err != nil || len(instances) != 0 {
+		t.Fatalf("first Next() = %v, %v, want no instances", instances, err)
+	}
+
+	// 注册后在下一次轮询时返回
+	if err := r.Register(ctx, testInstance("grpc://10.0.0.1:9000")); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	instances, err := w.Next()
+	if err != nil || len(instances) != 1 {
+		t.Fatalf("Next() = %v, %v, want one instance", instances, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Next() took %s with a 10ms poll interval", elapsed)
+	}
+}
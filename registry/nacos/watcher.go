@@ -0,0 +1,59 @@
+package nacos
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+)
+
+var _ registry.Watcher = (*watcher)(nil)
+
+// watcher 按 pollInterval 定期查询实例列表，实例变化时返回
+type watcher struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	registry    *Registry
+	serviceName string
+	last        []*registry.ServiceInstance
+	first       bool
+}
+
+func newWatcher(ctx context.Context, r *Registry, serviceName string) *watcher {
+	w := &watcher{registry: r, serviceName: serviceName, first: true}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	return w
+}
+
+// Next 返回变化后的实例列表，首次调用立即返回
+func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
+	for {
+		if !w.first {
+			select {
+			case <-w.ctx.Done():
+				return nil, w.ctx.Err()
+			case <-time.After(w.registry.pollInterval):
+			}
+		}
+		items, err := w.registry.GetService(w.ctx, w.serviceName)
+		if err != nil {
+			if w.first {
+				w.first = false
+				return nil, err
+			}
+			continue
+		}
+		if w.first || !reflect.DeepEqual(items, w.last) {
+			w.first = false
+			w.last = items
+			return items, nil
+		}
+	}
+}
+
+// Stop 停止监听
+func (w *watcher) Stop() error {
+	w.cancel()
+	return nil
+}
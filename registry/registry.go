@@ -1,40 +1,201 @@
 package registry
 
 import (
+	"context"
 	"fmt"
+	"io"
 
 	"github.com/addls/go-boot/config"
 	"github.com/addls/go-boot/registry/consul"
 	"github.com/addls/go-boot/registry/etcd"
+	"github.com/addls/go-boot/registry/file"
+	"github.com/addls/go-boot/registry/kubernetes"
+	"github.com/addls/go-boot/registry/nacos"
+	"github.com/addls/go-boot/registry/static"
 	"github.com/go-kratos/kratos/v2/registry"
 )
 
-// NewRegistrar 根据配置创建服务注册中心
-// 支持 etcd、consul 等常见注册中心
-func NewRegistrar(cfg *config.Discovery) (registry.Registrar, error) {
-	if cfg == nil || !cfg.Register {
+var (
+	_ registry.Registrar = (*Registry)(nil)
+	_ registry.Discovery = (*Registry)(nil)
+)
+
+// Registry 注册中心组件
+// 持有唯一的注册中心客户端，同时用于服务注册与服务发现，应用停止时关闭
+type Registry struct {
+	backend  registry.Discovery
+	register bool
+}
+
+// New 根据配置创建注册中心组件
+// 支持 etcd、consul、nacos、kubernetes 等常见注册中心，以及用于本地开发与测试的 static、file
+// 配置了 registries 时组合多个注册中心
+// 未启用服务注册且未配置注册中心地址时返回 nil
+func New(cfg *config.Discovery) (*Registry, error) {
+	if cfg == nil {
 		return nil, nil
 	}
 
-	if len(cfg.Endpoints) == 0 {
-		return nil, fmt.Errorf("endpoints cannot be empty when register is enabled")
+	var (
+		backend registry.Discovery
+		err     error
+	)
+	// 多个注册中心：服务注册到每个开启注册的注册中心，服务发现合并各注册中心的实例
+	if len(cfg.Registries) > 0 {
+		m, err := newMulti(cfg.Registries)
+		if err != nil || m == nil {
+			return nil, err
+		}
+		return &Registry{backend: m, register: m.register()}, nil
 	}
 
 	switch cfg.Type {
-	case "etcd":
-		return etcd.NewRegistrar(cfg)
-	case "consul":
-		return consul.NewRegistrar(cfg)
-	case "nacos":
-		// TODO: 实现 nacos 支持
-		return nil, fmt.Errorf("nacos registry not implemented yet")
+	// 不需要注册中心地址的类型
+	case "kubernetes":
+		backend, err = kubernetes.NewDiscovery(cfg)
+	case "static":
+		backend, err = static.NewDiscovery(cfg)
+	case "file":
+		backend, err = file.NewDiscovery(cfg)
 	default:
-		return nil, fmt.Errorf("unsupported registry type: %s", cfg.Type)
+		if len(cfg.Endpoints) == 0 {
+			if cfg.Register {
+				return nil, fmt.Errorf("endpoints cannot be empty when register is enabled")
+			}
+			// 注册中心未配置地址时不启用服务发现
+			return nil, nil
+		}
+		switch cfg.Type {
+		case "etcd":
+			backend, err = etcd.New(cfg)
+		case "consul":
+			backend, err = consul.New(cfg)
+		case "nacos":
+			backend, err = nacos.New(cfg)
+		default:
+			return nil, fmt.Errorf("unsupported registry type: %s", cfg.Type)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Registry{backend: backend, register: cfg.Register}, nil
+}
+
+// Registrar 返回服务注册中心
+// 未开启服务注册或注册中心只支持服务发现（kubernetes 由 Service 与就绪探针完成注册，static、file 只做服务发现）时返回 nil
+func (r *Registry) Registrar() registry.Registrar {
+	if r == nil || !r.register {
+		return nil
+	}
+	if _, ok := r.backend.(registry.Registrar); !ok {
+		return nil
 	}
+	return r
+}
+
+// Discovery 返回服务发现客户端
+func (r *Registry) Discovery() registry.Discovery {
+	if r == nil {
+		return nil
+	}
+	return r
+}
+
+// Register 注册服务实例
+func (r *Registry) Register(ctx context.Context, ins *registry.ServiceInstance) error {
+	registrar, ok := r.backend.(registry.Registrar)
+	if !ok {
+		return fmt.Errorf("registry does not support registration")
+	}
+	return registrar.Register(ctx, ins)
+}
+
+// Deregister 注销服务实例
+func (r *Registry) Deregister(ctx context.Context, ins *registry.ServiceInstance) error {
+	registrar, ok := r.backend.(registry.Registrar)
+	if !ok {
+		return fmt.Errorf("registry does not support registration")
+	}
+	return registrar.Deregister(ctx, ins)
+}
+
+// GetService 获取服务实例
+func (r *Registry) GetService(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	return r.backend.GetService(ctx, serviceName)
+}
+
+// Watch 监听服务实例变化
+func (r *Registry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	return r.backend.Watch(ctx, serviceName)
+}
+
+// Registered 检查服务实例是否仍在注册中心（组合多个注册中心时需在每个开启注册的注册中心中），查询失败视为未注册
+func (r *Registry) Registered(ctx context.Context, ins *registry.ServiceInstance) bool {
+	if m, ok := r.backend.(*multiRegistry); ok {
+		return m.registered(ctx, ins)
+	}
+	instances, err := r.backend.GetService(ctx, ins.Name)
+	if err != nil {
+		return false
+	}
+	for _, in := range instances {
+		if in.ID == ins.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// Services 列出注册中心中的服务名称，注册中心不支持时返回错误
+func (r *Registry) Services(ctx context.Context) ([]string, error) {
+	lister, ok := r.backend.(interface {
+		Services(context.Context) ([]string, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("registry does not support listing services")
+	}
+	return lister.Services(ctx)
+}
+
+// Health 检查与注册中心的连接
+func (r *Registry) Health(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+	if h, ok := r.backend.(interface{ Health(context.Context) error }); ok {
+		return h.Health(ctx)
+	}
+	return nil
+}
+
+// Close 关闭注册中心客户端
+// 应在服务注销之后调用
+func (r *Registry) Close() error {
+	if r == nil {
+		return nil
+	}
+	if c, ok := r.backend.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// NewRegistrar 根据配置创建服务注册中心
+// 支持 etcd、consul、nacos 等常见注册中心
+func NewRegistrar(cfg *config.Discovery) (registry.Registrar, error) {
+	if cfg == nil || (!cfg.Register && len(cfg.Registries) == 0) {
+		return nil, nil
+	}
+	r, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return r.Registrar(), nil
 }
 
 // NewDiscovery 根据配置创建服务发现客户端
-// 支持 etcd、consul 等常见注册中心
+// 支持 etcd、consul、nacos、kubernetes 等常见注册中心，以及用于本地开发与测试的 static、file
 // 如果 cfg 为 nil，则使用全局配置（通过 config.GetGlobalConfig() 获取）
 func NewDiscovery(cfg *config.Discovery) (registry.Discovery, error) {
 	// 如果未传入配置，使用全局配置
@@ -44,19 +205,9 @@ func NewDiscovery(cfg *config.Discovery) (registry.Discovery, error) {
 		}
 	}
 
-	if cfg == nil || len(cfg.Endpoints) == 0 {
-		return nil, nil
-	}
-
-	switch cfg.Type {
-	case "etcd":
-		return etcd.NewDiscovery(cfg)
-	case "consul":
-		return consul.NewDiscovery(cfg)
-	case "nacos":
-		// TODO: 实现 nacos 支持
-		return nil, fmt.Errorf("nacos discovery not implemented yet")
-	default:
-		return nil, fmt.Errorf("unsupported discovery type: %s", cfg.Type)
+	r, err := New(cfg)
+	if err != nil {
+		return nil, err
 	}
+	return r.Discovery(), nil
 }
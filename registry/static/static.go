@@ -0,0 +1,131 @@
+package static
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/addls/go-boot/config"
+	"github.com/go-kratos/kratos/v2/registry"
+)
+
+var _ registry.Discovery = (*Discovery)(nil)
+
+// Discovery 静态服务发现，服务地址直接来自配置
+// 适用于本地开发与集成测试，不依赖任何注册中心
+type Discovery struct {
+	mu       sync.RWMutex
+	services map[string][]*registry.ServiceInstance
+	watchers map[*watcher]struct{}
+}
+
+// NewDiscovery 根据 discovery.services 创建静态服务发现
+func NewDiscovery(cfg *config.Discovery) (registry.Discovery, error) {
+	return New(cfg.Services), nil
+}
+
+// New 创建静态服务发现
+// services 为服务名到地址列表的映射，地址格式为 grpc://127.0.0.1:9000、http://127.0.0.1:8000，省略协议时为 grpc
+func New(services map[string][]string) *Discovery {
+	d := &Discovery{watchers: make(map[*watcher]struct{})}
+	d.Update(services)
+	return d
+}
+
+// Update 替换服务地址并通知所有监听者
+func (d *Discovery) Update(services map[string][]string) {
+	instances := make(map[string][]*registry.ServiceInstance, len(services))
+	for name, addrs := range services {
+		items := make([]*registry.ServiceInstance, 0, len(addrs))
+		for _, addr := range addrs {
+			if !strings.Contains(addr, "://") {
+				addr = "grpc://" + addr
+			}
+			items = append(items, &registry.ServiceInstance{
+				ID:        addr,
+				Name:      name,
+				Endpoints: []string{addr},
+			})
+		}
+		instances[name] = items
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.services = instances
+	for w := range d.watchers {
+		w.notify()
+	}
+}
+
+// GetService 获取服务实例
+func (d *Discovery) GetService(_ context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.services[serviceName], nil
+}
+
+// Services 列出配置的服务名称
+func (d *Discovery) Services(context.Context) ([]string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	services := make([]string, 0, len(d.services))
+	for name := range d.services {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// Watch 监听服务实例变化
+func (d *Discovery) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	w := &watcher{
+		discovery:   d,
+		serviceName: serviceName,
+		changed:     make(chan struct{}, 1),
+	}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.notify()
+
+	d.mu.Lock()
+	d.watchers[w] = struct{}{}
+	d.mu.Unlock()
+	return w, nil
+}
+
+var _ registry.Watcher = (*watcher)(nil)
+
+type watcher struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	discovery   *Discovery
+	serviceName string
+	changed     chan struct{}
+}
+
+func (w *watcher) notify() {
+	select {
+	case w.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Next 返回服务实例，首次调用立即返回，之后在地址更新时返回
+func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
+	select {
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	case <-w.changed:
+	}
+	return w.discovery.GetService(w.ctx, w.serviceName)
+}
+
+// Stop 停止监听
+func (w *watcher) Stop() error {
+	w.cancel()
+	w.discovery.mu.Lock()
+	delete(w.discovery.watchers, w)
+	w.discovery.mu.Unlock()
+	return nil
+}
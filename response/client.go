@@ -0,0 +1,123 @@
+package response
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/addls/go-boot/common"
+	"github.com/go-kratos/kratos/v2/errors"
+	kratosHTTP "github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// envelope 客户端解码使用的响应结构
+// 兼容统一结构、problem+json 与 Kratos 默认错误格式
+type envelope struct {
+	Code     *int              `json:"code"`
+	Message  string            `json:"msg"`
+	Data     json.RawMessage   `json:"data"`
+	Reason   string            `json:"reason"`
+	Metadata map[string]string `json:"metadata"`
+	Details  []FieldError      `json:"details"`
+
+	Status   int    `json:"status"`  // problem+json
+	Detail   string `json:"detail"`  // problem+json
+	KMessage string `json:"message"` // Kratos 默认错误格式
+}
+
+// error 将错误响应转换为 Kratos 错误，成功响应返回 nil
+func (e *envelope) error(problem bool) *errors.Error {
+	var (
+		code    int
+		message string
+	)
+	switch {
+	case e.Code != nil && *e.Code != common.HTTPStatusOK:
+		code, message = *e.Code, e.Message
+		if message == "" {
+			message = e.KMessage
+		}
+	case problem && e.Status != 0:
+		code, message = e.Status, e.Detail
+	default:
+		return nil
+	}
+
+	se := errors.New(code, e.Reason, message)
+	if len(e.Metadata)+len(e.Details) > 0 {
+		md := make(map[string]string, len(e.Metadata)+len(e.Details))
+		for k, v := range e.Metadata {
+			md[k] = v
+		}
+		for _, d := range e.Details {
+			md[FieldErrorPrefix+d.Field] = d.Message
+		}
+		se = se.WithMetadata(md)
+	}
+	return se
+}
+
+// ResponseDecoder 统一响应解码器，用于 Kratos HTTP 客户端
+// 将统一结构中的 data 解码到 reply；非统一结构的响应（如原样输出）直接解码；空响应体（如 204）不解码
+func ResponseDecoder(_ context.Context, res *http.Response, v interface{}) error {
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	codec := kratosHTTP.CodecForResponse(res)
+	if codec.Name() == "json" {
+		var env envelope
+		if json.Unmarshal(data, &env) == nil && env.Code != nil {
+			if len(env.Data) == 0 || string(env.Data) == "null" {
+				return nil
+			}
+			data = env.Data
+		}
+	}
+	return codec.Unmarshal(data, v)
+}
+
+// ErrorDecoder 统一错误解码器，用于 Kratos HTTP 客户端
+// HTTP 状态码为 200 但 code 不为 200 的统一结构同样视为错误，还原为保留 reason 与 metadata 的 *errors.Error
+func ErrorDecoder(ctx context.Context, res *http.Response) error {
+	data, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return errors.New(res.StatusCode, errors.UnknownReason, "").WithCause(err)
+	}
+	// 成功响应需要交给 ResponseDecoder 继续读取
+	res.Body = io.NopCloser(bytes.NewReader(data))
+
+	if subtype := contentSubtype(res); strings.HasSuffix(subtype, "json") {
+		var env envelope
+		if json.Unmarshal(data, &env) == nil {
+			if se := env.error(subtype == "problem+json"); se != nil {
+				return se
+			}
+		}
+	}
+	return kratosHTTP.DefaultErrorDecoder(ctx, res)
+}
+
+// ClientOptions 返回解码统一结构的 Kratos HTTP 客户端选项
+// 例如：http.NewClient(ctx, append(response.ClientOptions(), http.WithEndpoint(addr))...)
+func ClientOptions() []kratosHTTP.ClientOption {
+	return []kratosHTTP.ClientOption{
+		kratosHTTP.WithResponseDecoder(ResponseDecoder),
+		kratosHTTP.WithErrorDecoder(ErrorDecoder),
+	}
+}
+
+// contentSubtype 获取响应 Content-Type 的子类型（如 json、problem+json）
+func contentSubtype(res *http.Response) string {
+	contentType, _, _ := strings.Cut(res.Header.Get("Content-Type"), ";")
+	_, subtype, _ := strings.Cut(strings.TrimSpace(contentType), "/")
+	return subtype
+}
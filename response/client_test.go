@@ -0,0 +1,129 @@
+package response
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// newResponse 创建 HTTP 响应，contentType 为空时不设置 Content-Type
+func newResponse(status int, contentType, body string) *http.Response {
+	res := &http.Response{StatusCode: status, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body))}
+	if contentType != "" {
+		res.Header.Set("Content-Type", contentType)
+	}
+	return res
+}
+
+func TestErrorDecoder(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		want        *errors.Error // nil 表示成功
+	}{
+		{
+			name: "envelope success", status: http.StatusOK, contentType: "application/json",
+			body: `{"code":200,"msg":"ok","data":{"name":"order-1"}}`,
+		},
+		{
+			name: "envelope error with status 200", status: http.StatusOK, contentType: "application/json; charset=utf-8",
+			body: `{"code":404,"msg":"user not found","reason":"USER_NOT_FOUND","metadata":{"id":"42"},"details":[{"field":"id","message":"unknown id"}]}`,
+			want: errors.New(404, "USER_NOT_FOUND", "user not found").WithMetadata(map[string]string{"id": "42", "field.id": "unknown id"}),
+		},
+		{
+			name: "envelope error with matching status", status: http.StatusBadRequest, contentType: "application/json",
+			body: `{"code":400,"msg":"invalid email","reason":"INVALID_ARGUMENT"}`,
+			want: errors.New(400, "INVALID_ARGUMENT", "invalid email"),
+		},
+		{
+			name: "problem+json", status: http.StatusUnprocessableEntity, contentType: "application/problem+json",
+			body: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"invalid email","reason":"INVALID_ARGUMENT","metadata":{"field.email":"bad format"}}`,
+			want: errors.New(422, "INVALID_ARGUMENT", "invalid email").WithMetadata(map[string]string{"field.email": "bad format"}),
+		},
+		{
+			name: "kratos default error", status: http.StatusServiceUnavailable, contentType: "application/json",
+			body: `{"code":503,"reason":"DB_UNAVAILABLE","message":"database is down","metadata":{"db":"order"}}`,
+			want: errors.New(503, "DB_UNAVAILABLE", "database is down").WithMetadata(map[string]string{"db": "order"}),
+		},
+		{
+			name: "raw json success", status: http.StatusOK, contentType: "application/json",
+			body: `{"name":"order-1"}`,
+		},
+		{
+			name: "non-json error", status: http.StatusBadGateway, contentType: "text/html",
+			body: `<html>bad gateway</html>`,
+			want: errors.New(502, errors.UnknownReason, ""),
+		},
+		{name: "empty success", status: http.StatusNoContent},
+		{
+			name: "empty error", status: http.StatusInternalServerError, contentType: "application/json",
+			want: errors.New(500, errors.UnknownReason, ""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := newResponse(tt.status, tt.contentType, tt.body)
+			err := ErrorDecoder(context.Background(), res)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("ErrorDecoder() error = %v, want nil", err)
+				}
+				// 成功响应的 body 仍可由 ResponseDecoder 读取
+				if data, _ := io.ReadAll(res.Body); string(data) != tt.body {
+					t.Fatalf("body after ErrorDecoder() = %q, want %q", data, tt.body)
+				}
+				return
+			}
+			se := errors.FromError(err)
+			if se.Code != tt.want.Code || se.Reason != tt.want.Reason || se.Message != tt.want.Message || !sameMetadata(se.Metadata, tt.want.Metadata) {
+				t.Fatalf("ErrorDecoder() = %d %s %q %v, want %d %s %q %v",
+					se.Code, se.Reason, se.Message, se.Metadata, tt.want.Code, tt.want.Reason, tt.want.Message, tt.want.Metadata)
+			}
+		})
+	}
+}
+
+// sameMetadata 比较元数据，nil 与空 map 视为相同
+func sameMetadata(a, b map[string]string) bool {
+	return len(a) == len(b) && (len(a) == 0 || reflect.DeepEqual(a, b))
+}
+
+func TestResponseDecoder(t *testing.T) {
+	type order struct {
+		Name string `json:"name"`
+	}
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        order
+		wantErr     bool
+	}{
+		{name: "envelope", contentType: "application/json", body: `{"code":200,"msg":"ok","data":{"name":"order-1"}}`, want: order{Name: "order-1"}},
+		{name: "envelope without data", contentType: "application/json", body: `{"code":200,"msg":"ok"}`},
+		{name: "envelope with null data", contentType: "application/json", body: `{"code":200,"msg":"ok","data":null}`},
+		{name: "raw json", contentType: "application/json", body: `{"name":"order-1"}`, want: order{Name: "order-1"}},
+		{name: "non-json", contentType: "text/plain", body: "order-1", wantErr: true},
+		{name: "empty body", contentType: "application/json"},
+		{name: "empty body without content type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got order
+			err := ResponseDecoder(context.Background(), newResponse(http.StatusOK, tt.contentType, tt.body), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResponseDecoder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ResponseDecoder() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/encoding"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// 内置的内容类型子类型
+const (
+	subtypeJSON  = "json"
+	subtypeProto = "x-protobuf"
+)
+
+// negotiate 根据 Accept 请求头选择编码子类型
+// 支持 json（默认）、application/x-protobuf（及 application/protobuf、application/proto）和通过 WithCodec 注册的自定义编码
+// 按 q 权重从高到低选择第一个支持的类型，q=0 表示不接受；权重相同时保持请求头中的顺序
+func negotiate(r *http.Request, o *options) string {
+	type weighted struct {
+		subtype string
+		q       float64
+	}
+	var accepted []weighted
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		_, subtype, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			continue
+		}
+		// application/problem+json -> json
+		if _, suffix, ok := strings.Cut(subtype, "+"); ok {
+			subtype = suffix
+		}
+		switch subtype {
+		case "*", subtypeJSON:
+			subtype = subtypeJSON
+		case subtypeProto, "protobuf", "proto":
+			subtype = subtypeProto
+		default:
+			if _, ok := o.codecs[subtype]; !ok {
+				continue
+			}
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			accepted = append(accepted, weighted{subtype, q})
+		}
+	}
+	sort.SliceStable(accepted, func(i, j int) bool { return accepted[i].q > accepted[j].q })
+	if len(accepted) > 0 {
+		return accepted[0].subtype
+	}
+	return subtypeJSON
+}
+
+// marshal 按协商结果编码响应，返回响应体与 Content-Type
+func marshal(r *http.Request, resp *Response, o *options) ([]byte, string, error) {
+	switch subtype := negotiate(r, o); subtype {
+	case subtypeProto:
+		// protobuf 无法表达统一结构，直接输出原始消息；非 proto 数据回退为 JSON
+		if m, ok := resp.Data.(proto.Message); ok {
+			data, err := proto.Marshal(m)
+			return data, "application/x-protobuf", err
+		}
+	case subtypeJSON:
+	default:
+		data, err := o.codecs[subtype].Marshal(resp)
+		return data, "application/" + subtype, err
+	}
+	data, err := marshalJSON(resp, o)
+	return data, "application/json", err
+}
+
+// marshalJSON 使用 JSON 编码统一结构
+// proto 消息使用 protojson 编码（枚举名、int64 字符串、Well-Known Types 等），其他数据使用 encoding/json
+func marshalJSON(resp *Response, o *options) ([]byte, error) {
+	data, err := marshalData(resp.Data, o)
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		wrapped := *resp
+		wrapped.Data = data
+		resp = &wrapped
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(resp); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// marshalData 预编码需要特殊处理的数据：proto 消息与分页列表，其他数据返回 nil
+// 分页列表的字段名与 proto 消息使用相同的命名规则，total 与 protojson 的 int64 一样编码为字符串
+func marshalData(v interface{}, o *options) (interface{}, error) {
+	switch data := v.(type) {
+	case proto.Message:
+		b, err := o.protoJSON.Marshal(data)
+		return json.RawMessage(b), err
+	case *Page:
+		list, err := marshalList(data.List, o)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"list":                               list,
+			"total":                              strconv.FormatInt(data.Total, 10),
+			"page":                               data.Page,
+			o.fieldName("page_size", "pageSize"): data.PageSize,
+		}, nil
+	case *CursorPage:
+		list, err := marshalList(data.List, o)
+		if err != nil {
+			return nil, err
+		}
+		page := map[string]interface{}{
+			"list":                             list,
+			o.fieldName("has_more", "hasMore"): data.HasMore,
+		}
+		if data.NextCursor != "" {
+			page[o.fieldName("next_cursor", "nextCursor")] = data.NextCursor
+		}
+		return page, nil
+	}
+	return nil, nil
+}
+
+// marshalValue 编码单个值，proto 消息使用 protojson
+func marshalValue(v interface{}, o *options) (json.RawMessage, error) {
+	if m, ok := v.(proto.Message); ok {
+		return o.protoJSON.Marshal(m)
+	}
+	return json.Marshal(v)
+}
+
+// defaultProtoJSON 默认 protojson 选项：使用 proto 字段名，与 encoding/json 编码生成代码的字段名保持一致
+var defaultProtoJSON = protojson.MarshalOptions{UseProtoNames: true}
+
+// fieldName 按 protojson 的命名选项选择字段名，使 Page、CursorPage 与 pagination.PageInfo、CursorInfo 的 JSON 字段一致
+func (o *options) fieldName(protoName, jsonName string) string {
+	if o.protoJSON.UseProtoNames {
+		return protoName
+	}
+	return jsonName
+}
+
+// WithProtoJSON 设置 proto 消息的 JSON 编码选项
+func WithProtoJSON(opts protojson.MarshalOptions) Option {
+	return func(o *options) {
+		o.protoJSON = opts
+	}
+}
+
+// WithCodec 注册自定义编码（如 xml、yaml、msgpack）
+// 客户端 Accept 为 application/<codec.Name()> 时使用该编码输出统一结构
+func WithCodec(codecs ...encoding.Codec) Option {
+	return func(o *options) {
+		for _, c := range codecs {
+			o.codecs[c.Name()] = c
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+// fakeCodec 自定义编码，输出固定内容
+type fakeCodec struct{}
+
+func (fakeCodec) Marshal(interface{}) ([]byte, error) { return []byte("yaml"), nil }
+func (fakeCodec) Unmarshal([]byte, interface{}) error { return nil }
+func (fakeCodec) Name() string                        { return "yaml" }
+
+func TestNegotiate(t *testing.T) {
+	o := newOptions(WithCodec(fakeCodec{}))
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", subtypeJSON},
+		{"*/*", subtypeJSON},
+		{"application/json", subtypeJSON},
+		{"application/problem+json", subtypeJSON},
+		{"application/x-protobuf", subtypeProto},
+		{"application/protobuf", subtypeProto},
+		{"application/proto", subtypeProto},
+		{"application/yaml", "yaml"},
+		{"text/html, application/yaml;q=0.9", "yaml"},
+		{"application/xml", subtypeJSON},
+		{"invalid;;, application/x-protobuf", subtypeProto},
+		{"application/json;q=0.5, application/x-protobuf", subtypeProto},
+		{"application/x-protobuf;q=0.8, application/yaml;q=0.9", "yaml"},
+		{"application/x-protobuf;q=0.8, */*;q=0.1", subtypeProto},
+		{"application/yaml;q=0, application/json;q=0.2", subtypeJSON},
+		{"application/x-protobuf;q=0", subtypeJSON},
+		{"application/yaml, application/x-protobuf", "yaml"},
+		{"application/xml;q=1, application/x-protobuf;q=0.3", subtypeProto},
+		{"application/x-protobuf;q=abc, application/yaml;q=0.9", subtypeProto},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", tt.accept)
+		if got := negotiate(r, o); got != tt.want {
+			t.Errorf("negotiate(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	info := &apipb.Method{Name: "Ping", RequestTypeUrl: "PingRequest"}
+	protoData, err := proto.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		accept      string
+		data        interface{}
+		contentType string
+		want        string
+	}{
+		{
+			name:        "proto message as protojson",
+			accept:      "application/json",
+			data:        info,
+			contentType: "application/json",
+			want:        `{"code":200,"msg":"success","data":{"name":"Ping","request_type_url":"PingRequest"}}`,
+		},
+		{
+			name:        "proto message as protobuf",
+			accept:      "application/x-protobuf",
+			data:        info,
+			contentType: "application/x-protobuf",
+			want:        string(protoData),
+		},
+		{
+			name:        "protobuf falls back to json for plain data",
+			accept:      "application/x-protobuf",
+			data:        map[string]int{"n": 1},
+			contentType: "application/json",
+			want:        `{"code":200,"msg":"success","data":{"n":1}}`,
+		},
+		{
+			name:        "page of proto messages",
+			accept:      "application/json",
+			data:        NewPage([]*apipb.Method{info}, 1, 1, 10),
+			contentType: "application/json",
+			want:        `{"code":200,"msg":"success","data":{"list":[{"name":"Ping","request_type_url":"PingRequest"}],"total":"1","page":1,"page_size":10}}`,
+		},
+		{
+			name:        "custom codec",
+			accept:      "application/yaml",
+			data:        info,
+			contentType: "application/yaml",
+			want:        "yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tt.accept)
+			data, contentType, err := marshal(r, Success(tt.data), newOptions(WithCodec(fakeCodec{})))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contentType != tt.contentType {
+				t.Fatalf("Content-Type = %q, want %q", contentType, tt.contentType)
+			}
+			if contentType == "application/json" {
+				// protojson 的输出包含随机空格，比较前统一格式
+				var got, want interface{}
+				if err := json.Unmarshal(data, &got); err != nil {
+					t.Fatal(err)
+				}
+				_ = json.Unmarshal([]byte(tt.want), &want)
+				gotJSON, _ := json.Marshal(got)
+				wantJSON, _ := json.Marshal(want)
+				data, tt.want = gotJSON, string(wantJSON)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("body = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
@@ -4,57 +4,199 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/addls/go-boot/common"
+	"github.com/addls/go-boot/i18n"
+	"github.com/go-kratos/kratos/v2/encoding"
 	"github.com/go-kratos/kratos/v2/errors"
+	kratosHTTP "github.com/go-kratos/kratos/v2/transport/http"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
+// ErrorMode 错误响应模式
+type ErrorMode string
+
+const (
+	ErrorModeEnvelope ErrorMode = "envelope" // 统一返回 HTTP 200，错误码在响应体的 code 字段中（默认）
+	ErrorModeStatus   ErrorMode = "status"   // HTTP 状态码与错误码一致，响应体仍为统一结构
+	ErrorModeProblem  ErrorMode = "problem"  // RFC 7807 application/problem+json
+)
+
+// Option 编码器选项
+type Option func(*options)
+
+type options struct {
+	errorMode  ErrorMode
+	protoJSON  protojson.MarshalOptions
+	codecs     map[string]encoding.Codec
+	translator *i18n.Translator
+
+	rawOperations map[string]bool
+	rawPaths      []string
+}
+
+// WithErrorMode 设置错误响应模式
+func WithErrorMode(mode ErrorMode) Option {
+	return func(o *options) {
+		o.errorMode = mode
+	}
+}
+
+// WithTranslator 设置错误消息翻译器，按 Accept-Language 返回对应语言的错误消息
+func WithTranslator(t *i18n.Translator) Option {
+	return func(o *options) {
+		o.translator = t
+	}
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		errorMode: ErrorModeEnvelope,
+		protoJSON: defaultProtoJSON,
+		codecs:    make(map[string]encoding.Codec),
+
+		rawOperations: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
 // ResponseEncoder 统一响应编码器
-// 自动将业务返回的数据包装成统一的 Response 结构
-func ResponseEncoder() func(http.ResponseWriter, *http.Request, interface{}) error {
+// 自动将业务返回的数据包装成统一的 Response 结构，并根据 Accept 请求头选择编码
+func ResponseEncoder(opts ...Option) func(http.ResponseWriter, *http.Request, interface{}) error {
+	o := newOptions(opts...)
 	return func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+		// 原样输出
+		if raw, ok := v.(*Raw); ok {
+			return encodeRaw(w, raw)
+		}
+		if isRaw(r, o) {
+			if err, ok := v.(error); ok {
+				kratosHTTP.DefaultErrorEncoder(w, r, err)
+				return nil
+			}
+			return kratosHTTP.DefaultResponseEncoder(w, r, v)
+		}
+
 		// 如果已经是 Response 类型，直接返回
 		if resp, ok := v.(*Response); ok {
-			return encodeResponse(w, resp)
+			return encodeResponse(w, r, resp, o)
 		}
 
 		// 如果是错误，转换为错误响应
 		if err, ok := v.(error); ok {
-			return encodeError(w, err)
+			return encodeError(w, r, err, o)
 		}
 
 		// 其他情况包装为成功响应
-		return encodeResponse(w, Success(v))
+		return encodeResponse(w, r, Success(v), o)
 	}
 }
 
 // ErrorEncoder 统一错误编码器
 // 将 Kratos errors 转换为统一的错误响应格式
-func ErrorEncoder() func(http.ResponseWriter, *http.Request, error) {
+func ErrorEncoder(opts ...Option) func(http.ResponseWriter, *http.Request, error) {
+	o := newOptions(opts...)
 	return func(w http.ResponseWriter, r *http.Request, err error) {
-		encodeError(w, err)
+		if isRaw(r, o) {
+			kratosHTTP.DefaultErrorEncoder(w, r, err)
+			return
+		}
+		encodeError(w, r, err, o)
 	}
 }
 
 // encodeResponse 编码响应
-func encodeResponse(w http.ResponseWriter, resp *Response) error {
-	w.Header().Set("Content-Type", "application/json")
+func encodeResponse(w http.ResponseWriter, r *http.Request, resp *Response, o *options) error {
+	data, contentType, err := marshal(r, resp, o)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(http.StatusOK)
-	return json.NewEncoder(w).Encode(resp)
+	_, err = w.Write(data)
+	return err
 }
 
 // encodeError 编码错误
-func encodeError(w http.ResponseWriter, err error) error {
-	// 尝试从 Kratos errors 中提取信息
-	se := errors.FromError(err)
-	if se != nil {
-		resp := Error(int(se.Code), se.Message)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK) // 统一返回 200，错误信息在 body 中
-		return json.NewEncoder(w).Encode(resp)
-	}
-
-	// 其他错误
-	resp := Error(500, err.Error())
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	return json.NewEncoder(w).Encode(resp)
+func encodeError(w http.ResponseWriter, r *http.Request, err error, o *options) error {
+	// 从 Kratos errors 中提取信息，非 Kratos 错误统一视为 500
+	resp := FromError(err)
+	resp.RequestID = requestID(w, r)
+	if o.translator != nil {
+		if message, language, ok := o.translator.Message(resp.Reason, errors.FromError(err).Metadata, r.Header.Get(i18n.HeaderAcceptLanguage)); ok {
+			resp.Message, resp.Error = message, message
+			w.Header().Set("Content-Language", language)
+		}
+	}
+
+	var (
+		status                = httpStatus(resp.Code)
+		body      interface{} = resp
+		mediaType             = "application/"
+	)
+	switch o.errorMode {
+	case ErrorModeProblem:
+		problem := NewProblem(status, resp.Message, r.URL.Path)
+		problem.Reason = resp.Reason
+		problem.Metadata = resp.Metadata
+		problem.RequestID = resp.RequestID
+		problem.Details = resp.Details
+		body, mediaType = problem, "application/problem+"
+	case ErrorModeStatus:
+	default:
+		status = http.StatusOK // 统一返回 200，错误信息在 body 中
+	}
+
+	// 错误响应与成功响应使用相同的编码协商
+	switch subtype := negotiate(r, o); subtype {
+	case subtypeProto:
+		// protobuf 无法表达统一结构与 problem：输出 Kratos 错误状态 errors.Status（与 Kratos 默认错误编码一致），
+		// 并且始终使用真实的 HTTP 状态码，避免 envelope 模式下客户端把 200 的错误响应按业务消息解码
+		st := &errors.Status{
+			Code:     int32(resp.Code),
+			Reason:   resp.Reason,
+			Message:  resp.Message,
+			Metadata: errors.FromError(err).Metadata,
+		}
+		data, err := proto.Marshal(st)
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(httpStatus(resp.Code))
+		_, err = w.Write(data)
+		return err
+	case subtypeJSON:
+		w.Header().Set("Content-Type", mediaType+subtypeJSON)
+		w.WriteHeader(status)
+		return json.NewEncoder(w).Encode(body)
+	default:
+		data, err := o.codecs[subtype].Marshal(body)
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", mediaType+subtype)
+		w.WriteHeader(status)
+		_, err = w.Write(data)
+		return err
+	}
+}
+
+// requestID 获取请求 ID：优先取 RequestID 中间件写入的响应头，其次取请求头
+func requestID(w http.ResponseWriter, r *http.Request) string {
+	if id := w.Header().Get(common.HeaderRequestID); id != "" {
+		return id
+	}
+	return r.Header.Get(common.HeaderRequestID)
+}
+
+// httpStatus 将错误码转换为 HTTP 状态码，非法状态码视为 500
+func httpStatus(code int) int {
+	if code < 100 || code > 599 {
+		return http.StatusInternalServerError
+	}
+	return code
 }
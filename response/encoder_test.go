@@ -0,0 +1,192 @@
+package response
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/addls/go-boot/common"
+	"github.com/go-kratos/kratos/v2/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestErrorEncoder(t *testing.T) {
+	notFound := WithFieldError(errors.NotFound("USER_NOT_FOUND", "user not found").WithMetadata(map[string]string{"id": "42"}), "id", "unknown id")
+
+	tests := []struct {
+		name        string
+		mode        ErrorMode
+		err         error
+		status      int
+		contentType string
+		want        map[string]interface{} // 响应体中需要校验的字段
+	}{
+		{
+			name:        "envelope",
+			mode:        ErrorModeEnvelope,
+			err:         notFound,
+			status:      http.StatusOK,
+			contentType: "application/json",
+			want: map[string]interface{}{
+				"code": 404.0, "msg": "user not found", "error": "user not found", "reason": "USER_NOT_FOUND",
+				"metadata":  map[string]interface{}{"id": "42"},
+				"details":   []interface{}{map[string]interface{}{"field": "id", "message": "unknown id"}},
+				"requestId": "req-1",
+			},
+		},
+		{
+			name:        "status",
+			mode:        ErrorModeStatus,
+			err:         notFound,
+			status:      http.StatusNotFound,
+			contentType: "application/json",
+			want:        map[string]interface{}{"code": 404.0, "reason": "USER_NOT_FOUND", "requestId": "req-1"},
+		},
+		{
+			name:        "problem",
+			mode:        ErrorModeProblem,
+			err:         notFound,
+			status:      http.StatusNotFound,
+			contentType: "application/problem+json",
+			want: map[string]interface{}{
+				"type": "about:blank", "title": "Not Found", "status": 404.0, "detail": "user not found",
+				"instance": "/users/42", "reason": "USER_NOT_FOUND", "requestId": "req-1",
+				"metadata": map[string]interface{}{"id": "42"},
+			},
+		},
+		{
+			name:        "plain error in status mode",
+			mode:        ErrorModeStatus,
+			err:         stderrors.New("boom"),
+			status:      http.StatusInternalServerError,
+			contentType: "application/json",
+			want:        map[string]interface{}{"code": 500.0},
+		},
+		{
+			name:        "invalid code in problem mode",
+			mode:        ErrorModeProblem,
+			err:         errors.New(1001, "BIZ_ERROR", "business error"),
+			status:      http.StatusInternalServerError,
+			contentType: "application/problem+json",
+			want:        map[string]interface{}{"status": 500.0, "reason": "BIZ_ERROR"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+			r.Header.Set(common.HeaderRequestID, "req-1")
+			w := httptest.NewRecorder()
+			ErrorEncoder(WithErrorMode(tt.mode))(w, r, tt.err)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.contentType {
+				t.Fatalf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			for k, want := range tt.want {
+				if !reflect.DeepEqual(body[k], want) {
+					t.Errorf("%s = %#v, want %#v", k, body[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestResponseEncoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		v      interface{}
+		status int
+		want   string
+	}{
+		{
+			name:   "wrap data",
+			v:      map[string]string{"name": "go-boot"},
+			status: http.StatusOK,
+			want:   `{"code":200,"msg":"success","data":{"name":"go-boot"}}`,
+		},
+		{
+			name:   "response as is",
+			v:      Error(409, "conflict"),
+			status: http.StatusOK,
+			want:   `{"code":409,"msg":"conflict","error":"conflict"}`,
+		},
+		{
+			name:   "error",
+			v:      errors.Conflict("DUPLICATED", "duplicated"),
+			status: http.StatusConflict,
+			want:   `{"code":409,"msg":"duplicated","error":"duplicated","reason":"DUPLICATED"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+			if err := ResponseEncoder(WithErrorMode(ErrorModeStatus))(w, r, tt.v); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Body.String(); got != tt.want+"\n" {
+				t.Fatalf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorEncoderNegotiation(t *testing.T) {
+	notFound := errors.NotFound("USER_NOT_FOUND", "user not found").WithMetadata(map[string]string{"id": "42"})
+
+	tests := []struct {
+		name        string
+		mode        ErrorMode
+		accept      string
+		status      int
+		contentType string
+	}{
+		{name: "protobuf in envelope mode uses the real status", mode: ErrorModeEnvelope, accept: "application/x-protobuf", status: http.StatusNotFound, contentType: "application/x-protobuf"},
+		{name: "protobuf in status mode", mode: ErrorModeStatus, accept: "application/x-protobuf", status: http.StatusNotFound, contentType: "application/x-protobuf"},
+		{name: "protobuf in problem mode", mode: ErrorModeProblem, accept: "application/x-protobuf", status: http.StatusNotFound, contentType: "application/x-protobuf"},
+		{name: "custom codec in envelope mode", mode: ErrorModeEnvelope, accept: "application/yaml", status: http.StatusOK, contentType: "application/yaml"},
+		{name: "custom codec in problem mode", mode: ErrorModeProblem, accept: "application/yaml", status: http.StatusNotFound, contentType: "application/problem+yaml"},
+		{name: "json preferred by weight", mode: ErrorModeEnvelope, accept: "application/x-protobuf;q=0.5, application/json", status: http.StatusOK, contentType: "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			ErrorEncoder(WithErrorMode(tt.mode), WithCodec(fakeCodec{}))(w, r, notFound)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.contentType {
+				t.Fatalf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			switch tt.contentType {
+			case "application/x-protobuf":
+				var st errors.Status
+				if err := proto.Unmarshal(w.Body.Bytes(), &st); err != nil {
+					t.Fatal(err)
+				}
+				if st.Code != 404 || st.Reason != "USER_NOT_FOUND" || st.Message != "user not found" || st.Metadata["id"] != "42" {
+					t.Fatalf("status = %v", &st)
+				}
+			case "application/yaml", "application/problem+yaml":
+				if got := w.Body.String(); got != "yaml" {
+					t.Fatalf("body = %q, want yaml", got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package response
+
+import (
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http/status"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpcStatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RetryAfterKey 建议重试间隔在错误元数据中的 key（如 "5s"），gRPC 错误会附带 RetryInfo
+const RetryAfterKey = "retryAfter"
+
+// WithRetryAfter 为错误添加建议重试间隔
+func WithRetryAfter(err *errors.Error, d time.Duration) *errors.Error {
+	md := make(map[string]string, len(err.Metadata)+1)
+	for k, v := range err.Metadata {
+		md[k] = v
+	}
+	md[RetryAfterKey] = d.String()
+	return err.WithMetadata(md)
+}
+
+// grpcError 携带 google.rpc 错误详情的 gRPC 错误
+type grpcError struct {
+	err    *errors.Error
+	status *grpcStatus.Status
+}
+
+func (e *grpcError) Error() string                  { return e.err.Error() }
+func (e *grpcError) Unwrap() error                  { return e.err }
+func (e *grpcError) GRPCStatus() *grpcStatus.Status { return e.status }
+
+// GRPCError 将错误转换为携带错误详情的 gRPC 错误，与 HTTP 统一结构保持一致：
+//   - ErrorInfo：reason 与元数据
+//   - BadRequest：字段级错误（元数据中 field. 前缀的条目）
+//   - RetryInfo：建议重试间隔（元数据中的 retryAfter）
+//   - RequestInfo：请求 ID
+func GRPCError(err error, requestID string) error {
+	se := errors.FromError(err)
+	if se == nil {
+		return nil
+	}
+	_, fields := splitMetadata(se.Metadata)
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: se.Reason, Metadata: se.Metadata}}
+	if len(fields) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(fields))
+		for i, f := range fields {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Field: f.Field, Description: f.Message}
+		}
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+	if d, err := time.ParseDuration(se.Metadata[RetryAfterKey]); err == nil {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(d)})
+	}
+	if requestID != "" {
+		details = append(details, &errdetails.RequestInfo{RequestId: requestID})
+	}
+
+	st := grpcStatus.New(status.ToGRPCCode(int(se.Code)), se.Message)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return &grpcError{err: se, status: st}
+}
+
+// FromGRPCError 将 gRPC 客户端收到的错误还原为 *errors.Error，与 HTTP 客户端的 ErrorDecoder 返回相同的错误类型
+// reason 与元数据取自 ErrorInfo，BadRequest 的字段级错误合并为 field. 前缀的元数据，
+// 调用方可以对两种传输协议使用同一套 errors.Reason / errors.FromError 处理逻辑
+func FromGRPCError(err error) *errors.Error {
+	se := errors.FromError(err)
+	st, ok := grpcStatus.FromError(err)
+	if se == nil || !ok {
+		return se
+	}
+
+	var md map[string]string
+	for _, detail := range st.Details() {
+		d, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		// go-boot 服务的 ErrorInfo 已包含字段级错误，非 go-boot 服务可能只返回 BadRequest
+		for _, v := range d.FieldViolations {
+			key := FieldErrorPrefix + v.Field
+			if _, exists := se.Metadata[key]; exists {
+				continue
+			}
+			if md == nil {
+				md = make(map[string]string, len(se.Metadata)+len(d.FieldViolations))
+				for k, v := range se.Metadata {
+					md[k] = v
+				}
+			}
+			md[key] = v.Description
+		}
+	}
+	if md != nil {
+		se = se.WithMetadata(md)
+	}
+	return se
+}
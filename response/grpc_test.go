@@ -0,0 +1,83 @@
+package response
+
+import (
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+)
+
+func TestFromGRPCError(t *testing.T) {
+	badRequest, _ := grpcStatus.New(codes.InvalidArgument, "invalid").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "email", Description: "invalid email"}},
+	})
+
+	tests := []struct {
+		name     string
+		err      error
+		code     int32
+		reason   string
+		metadata map[string]string
+	}{
+		{
+			name:     "go-boot error round trip",
+			err:      GRPCError(WithRetryAfter(WithFieldError(errors.BadRequest("INVALID_USER", "invalid user"), "email", "invalid email"), time.Second), "req-1"),
+			code:     400,
+			reason:   "INVALID_USER",
+			metadata: map[string]string{"field.email": "invalid email", RetryAfterKey: "1s"},
+		},
+		{
+			name:     "bad request only",
+			err:      badRequest.Err(),
+			code:     400,
+			reason:   errors.UnknownReason,
+			metadata: map[string]string{"field.email": "invalid email"},
+		},
+		{
+			name:   "plain status",
+			err:    grpcStatus.Error(codes.NotFound, "not found"),
+			code:   404,
+			reason: errors.UnknownReason,
+		},
+		{
+			name:   "non grpc error",
+			err:    stderrors.New("boom"),
+			code:   errors.UnknownCode,
+			reason: errors.UnknownReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := FromGRPCError(tt.err)
+			if se.Code != tt.code || se.Reason != tt.reason {
+				t.Fatalf("got code %d reason %q, want %d %q", se.Code, se.Reason, tt.code, tt.reason)
+			}
+			for k, v := range tt.metadata {
+				if se.Metadata[k] != v {
+					t.Errorf("metadata[%q] = %q, want %q", k, se.Metadata[k], v)
+				}
+			}
+			if errors.Reason(se) != tt.reason {
+				t.Errorf("errors.Reason() = %q, want %q", errors.Reason(se), tt.reason)
+			}
+		})
+	}
+
+	if FromGRPCError(nil) != nil {
+		t.Error("FromGRPCError(nil) should be nil")
+	}
+}
+
+// GRPCError 经过真实的 gRPC 传输后只剩 status，模拟客户端收到的错误
+func TestFromGRPCErrorOverStatus(t *testing.T) {
+	err := GRPCError(WithFieldError(errors.BadRequest("INVALID_USER", "invalid user"), "name", "required"), "")
+	st, _ := grpcStatus.FromError(err)
+	se := FromGRPCError(st.Err())
+	if se.Reason != "INVALID_USER" || se.Metadata["field.name"] != "required" {
+		t.Fatalf("got reason %q metadata %v", se.Reason, se.Metadata)
+	}
+}
@@ -0,0 +1,148 @@
+package response
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/addls/go-boot/api/pagination/v1"
+	"github.com/addls/go-boot/common"
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// ErrInvalidCursor 游标格式错误或签名校验失败
+var ErrInvalidCursor = errors.BadRequest("INVALID_CURSOR", "invalid cursor")
+
+// Page 页码分页列表
+// JSON 编码与 pagination.PageInfo 一致：字段名跟随 protojson 命名选项（默认 proto 字段名），total 为字符串
+type Page struct {
+	List     interface{} `json:"list"`         // 当前页数据
+	Total    int64       `json:"total,string"` // 总条数
+	Page     int         `json:"page"`         // 页码，从 1 开始
+	PageSize int         `json:"page_size"`    // 每页条数
+}
+
+// CursorPage 游标分页列表
+// JSON 编码与 pagination.CursorInfo 一致，字段名跟随 protojson 命名选项（默认 proto 字段名）
+type CursorPage struct {
+	List       interface{} `json:"list"`                  // 当前页数据
+	NextCursor string      `json:"next_cursor,omitempty"` // 下一页游标，没有更多数据时为空
+	HasMore    bool        `json:"has_more"`              // 是否还有更多数据
+}
+
+// NewPage 创建页码分页列表
+func NewPage(list interface{}, total int64, page, pageSize int) *Page {
+	return &Page{List: list, Total: total, Page: page, PageSize: pageSize}
+}
+
+// NewCursorPage 创建游标分页列表，nextCursor 为空表示没有更多数据
+func NewCursorPage(list interface{}, nextCursor string) *CursorPage {
+	return &CursorPage{List: list, NextCursor: nextCursor, HasMore: nextCursor != ""}
+}
+
+// Info 转换为 proto 分页信息
+func (p *Page) Info() *pagination.PageInfo {
+	return &pagination.PageInfo{Total: p.Total, Page: int32(p.Page), PageSize: int32(p.PageSize)}
+}
+
+// Info 转换为 proto 游标分页信息
+func (p *CursorPage) Info() *pagination.CursorInfo {
+	return &pagination.CursorInfo{NextCursor: p.NextCursor, HasMore: p.HasMore}
+}
+
+// NormalizePage 规范化页码与每页条数
+// 页码小于 1 时取 1，每页条数小于 1 时取默认值，超过上限时取上限
+func NormalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = common.DefaultPageSize
+	}
+	if pageSize > common.MaxPageSize {
+		pageSize = common.MaxPageSize
+	}
+	return page, pageSize
+}
+
+// PageFromRequest 从 proto 分页请求中读取规范化后的页码与每页条数
+func PageFromRequest(req *pagination.PageRequest) (page, pageSize int) {
+	return NormalizePage(int(req.GetPage()), int(req.GetPageSize()))
+}
+
+// Offset 计算分页查询的偏移量
+func Offset(page, pageSize int) int {
+	page, pageSize = NormalizePage(page, pageSize)
+	return (page - 1) * pageSize
+}
+
+// CursorCodec 游标编解码器
+// 游标为 base64url(JSON 载荷) + "." + base64url(HMAC-SHA256 签名)，对客户端不透明且不可篡改
+type CursorCodec struct {
+	secret []byte
+}
+
+// NewCursorCodec 创建游标编解码器，secret 用于签名，同一服务的所有实例必须一致
+func NewCursorCodec(secret []byte) *CursorCodec {
+	return &CursorCodec{secret: secret}
+}
+
+// Encode 将游标位置（如最后一条记录的 ID 与排序字段）编码为签名游标
+func (c *CursorCodec) Encode(v interface{}) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode 校验签名并解码游标，游标非法时返回 ErrInvalidCursor
+func (c *CursorCodec) Decode(cursor string, v interface{}) error {
+	encoded, signature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, c.sign(payload)) {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// marshalList 编码分页列表数据
+// nil 切片输出为 []，proto 消息元素使用 protojson 编码
+func marshalList(list interface{}, o *options) (interface{}, error) {
+	rv := reflect.ValueOf(list)
+	if !rv.IsValid() {
+		return []json.RawMessage{}, nil
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return list, nil
+	}
+	items := make([]json.RawMessage, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		data, err := marshalValue(rv.Index(i).Interface(), o)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, data)
+	}
+	return items, nil
+}
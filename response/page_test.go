@@ -0,0 +1,204 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/addls/go-boot/api/pagination/v1"
+	"github.com/go-kratos/kratos/v2/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNormalizePage(t *testing.T) {
+	tests := []struct {
+		name               string
+		page, pageSize     int
+		wantPage, wantSize int
+		wantOffset         int
+	}{
+		{name: "valid", page: 3, pageSize: 10, wantPage: 3, wantSize: 10, wantOffset: 20},
+		{name: "zero", wantPage: 1, wantSize: 20, wantOffset: 0},
+		{name: "negative", page: -1, pageSize: -5, wantPage: 1, wantSize: 20, wantOffset: 0},
+		{name: "over max", page: 2, pageSize: 1000, wantPage: 2, wantSize: 100, wantOffset: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, size := NormalizePage(tt.page, tt.pageSize)
+			if page != tt.wantPage || size != tt.wantSize {
+				t.Fatalf("NormalizePage() = %d, %d, want %d, %d", page, size, tt.wantPage, tt.wantSize)
+			}
+			if got := Offset(tt.page, tt.pageSize); got != tt.wantOffset {
+				t.Fatalf("Offset() = %d, want %d", got, tt.wantOffset)
+			}
+		})
+	}
+
+	page, size := PageFromRequest(&pagination.PageRequest{Page: 2, PageSize: 500})
+	if page != 2 || size != 100 {
+		t.Fatalf("PageFromRequest() = %d, %d, want 2, 100", page, size)
+	}
+	if page, size := PageFromRequest(nil); page != 1 || size != 20 {
+		t.Fatalf("PageFromRequest(nil) = %d, %d, want 1, 20", page, size)
+	}
+}
+
+func TestCursorPage(t *testing.T) {
+	tests := []struct {
+		name        string
+		nextCursor  string
+		wantHasMore bool
+	}{
+		{name: "has more", nextCursor: "abc", wantHasMore: true},
+		{name: "last page"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewCursorPage(nil, tt.nextCursor)
+			if p.HasMore != tt.wantHasMore || p.Info().GetHasMore() != tt.wantHasMore || p.Info().GetNextCursor() != tt.nextCursor {
+				t.Fatalf("NewCursorPage() = %+v", p)
+			}
+		})
+	}
+}
+
+func TestCursorCodec(t *testing.T) {
+	type position struct {
+		ID        int64  `json:"id"`
+		CreatedAt string `json:"createdAt"`
+	}
+	codec := NewCursorCodec([]byte("secret"))
+	cursor, err := codec.Encode(position{ID: 42, CreatedAt: "2024-01-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, _, _ := strings.Cut(cursor, ".")
+
+	tests := []struct {
+		name    string
+		codec   *CursorCodec
+		cursor  string
+		want    position
+		wantErr bool
+	}{
+		{name: "round trip", codec: codec, cursor: cursor, want: position{ID: 42, CreatedAt: "2024-01-01"}},
+		{name: "other secret", codec: NewCursorCodec([]byte("other")), cursor: cursor, wantErr: true},
+		{name: "tampered payload", codec: codec, cursor: "eyJpZCI6NDN9." + strings.SplitN(cursor, ".", 2)[1], wantErr: true},
+		{name: "missing signature", codec: codec, cursor: payload, wantErr: true},
+		{name: "invalid base64", codec: codec, cursor: "!!!.???", wantErr: true},
+		{name: "empty", codec: codec, cursor: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got position
+			err := tt.codec.Decode(tt.cursor, &got)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Fatalf("Decode() error = %v, want ErrInvalidCursor", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("Decode() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalList(t *testing.T) {
+	tests := []struct {
+		name string
+		list interface{}
+		want string
+	}{
+		{name: "nil", list: nil, want: `[]`},
+		{name: "nil slice", list: []string(nil), want: `[]`},
+		{name: "structs", list: []struct {
+			ID int `json:"id"`
+		}{{1}, {2}}, want: `[{"id":1},{"id":2}]`},
+		{name: "proto messages", list: []*pagination.CursorInfo{{NextCursor: "a", HasMore: true}}, want: `[{"has_more":true,"next_cursor":"a"}]`},
+		{name: "not a slice", list: map[string]int{"a": 1}, want: `{"a":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := marshalList(tt.list, newOptions())
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(list)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// protojson 的输出包含随机空格，比较前统一格式
+			var got interface{}
+			_ = json.Unmarshal(data, &got)
+			data, _ = json.Marshal(got)
+			if string(data) != tt.want {
+				t.Fatalf("marshalList() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageMatchesProto(t *testing.T) {
+	// decode 解析 JSON，忽略列表字段
+	decode := func(t *testing.T, data []byte) map[string]interface{} {
+		t.Helper()
+		var v map[string]interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			t.Fatal(err)
+		}
+		delete(v, "list")
+		return v
+	}
+
+	page := NewPage([]string{"a"}, 12, 2, 10)
+	tests := []struct {
+		name  string
+		data  interface{}
+		proto proto.Message
+	}{
+		{name: "page", data: page, proto: page.Info()},
+		{name: "cursor page", data: NewCursorPage([]string{"a"}, "next"), proto: NewCursorPage(nil, "next").Info()},
+		{name: "last cursor page", data: NewCursorPage([]string{"a"}, ""), proto: NewCursorPage(nil, "").Info()},
+	}
+	for _, useProtoNames := range []bool{true, false} {
+		opts := protojson.MarshalOptions{UseProtoNames: useProtoNames}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				data, _, err := marshal(r, Success(tt.data), newOptions(WithProtoJSON(opts)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				var resp struct {
+					Data json.RawMessage `json:"data"`
+				}
+				if err := json.Unmarshal(data, &resp); err != nil {
+					t.Fatal(err)
+				}
+				// 输出零值字段，比较字段名与类型；has_more 为 false 时 protojson 同样需要输出
+				want, err := protojson.MarshalOptions{UseProtoNames: useProtoNames, EmitUnpopulated: true}.Marshal(tt.proto)
+				if err != nil {
+					t.Fatal(err)
+				}
+				wantFields := decode(t, want)
+				if c, ok := tt.proto.(*pagination.CursorInfo); ok && c.NextCursor == "" {
+					// 没有更多数据时不输出游标
+					delete(wantFields, "next_cursor")
+					delete(wantFields, "nextCursor")
+				}
+				if got := decode(t, resp.Data); !reflect.DeepEqual(got, wantFields) {
+					t.Fatalf("useProtoNames=%v: data = %v, want %v", useProtoNames, got, wantFields)
+				}
+			})
+		}
+	}
+}
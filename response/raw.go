@@ -0,0 +1,87 @@
+package response
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+// Raw 原样输出的响应
+// 业务返回 *Raw 时不包装统一结构，适用于 webhook、文件下载、OAuth 回调、第三方指定格式等场景
+type Raw struct {
+	StatusCode  int         // HTTP 状态码（默认 200）
+	ContentType string      // Content-Type（可选）
+	Header      http.Header // 额外响应头（可选）
+	Body        io.Reader   // 响应体，支持流式输出；实现 io.Closer 时输出完成后自动关闭
+}
+
+// NewRaw 创建原样输出的响应
+func NewRaw(contentType string, body []byte) *Raw {
+	return &Raw{ContentType: contentType, Body: bytes.NewReader(body)}
+}
+
+// Stream 创建流式输出的响应
+func Stream(contentType string, body io.Reader) *Raw {
+	return &Raw{ContentType: contentType, Body: body}
+}
+
+// WithRawOperations 指定不包装统一结构的 operation（如 /api.payment.v1.Payment/Webhook）
+// 这些接口的响应与错误使用 Kratos 默认编码器输出
+func WithRawOperations(operations ...string) Option {
+	return func(o *options) {
+		for _, op := range operations {
+			o.rawOperations[op] = true
+		}
+	}
+}
+
+// WithRawPaths 指定不包装统一结构的路径前缀（如 /oauth/callback）
+// 这些路径的响应与错误使用 Kratos 默认编码器输出
+func WithRawPaths(prefixes ...string) Option {
+	return func(o *options) {
+		o.rawPaths = append(o.rawPaths, prefixes...)
+	}
+}
+
+// isRaw 判断请求是否跳过统一结构
+func isRaw(r *http.Request, o *options) bool {
+	if len(o.rawOperations) > 0 {
+		if info, ok := transport.FromServerContext(r.Context()); ok && o.rawOperations[info.Operation()] {
+			return true
+		}
+	}
+	for _, prefix := range o.rawPaths {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeRaw 原样输出响应
+func encodeRaw(w http.ResponseWriter, raw *Raw) error {
+	if closer, ok := raw.Body.(io.Closer); ok {
+		defer closer.Close()
+	}
+	for k, values := range raw.Header {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+	if raw.ContentType != "" {
+		w.Header().Set("Content-Type", raw.ContentType)
+	}
+	status := raw.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	if raw.Body == nil {
+		return nil
+	}
+	_, err := io.Copy(w, raw.Body)
+	return err
+}
@@ -1,13 +1,34 @@
 package response
 
-import "github.com/addls/go-boot/common"
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/addls/go-boot/common"
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// FieldErrorPrefix 字段级错误在错误元数据中的 key 前缀
+// 例如 metadata["field.email"] = "邮箱格式不正确"
+const FieldErrorPrefix = "field."
 
 // Response 统一响应结构
 type Response struct {
-	Code    int         `json:"code"`            // 状态码：200 表示成功，其他表示失败
-	Message string      `json:"msg"`             // 消息
-	Data    interface{} `json:"data,omitempty"`  // 数据（成功时返回）
-	Error   string      `json:"error,omitempty"` // 错误信息（失败时返回）
+	Code      int               `json:"code"`                       // 状态码：200 表示成功，其他表示失败
+	Message   string            `json:"msg"`                        // 消息
+	Data      interface{}       `json:"data,omitempty"`             // 数据（成功时返回）
+	Error     string            `json:"error,omitempty"`            // 错误信息（失败时返回）
+	Reason    string            `json:"reason,omitempty"`           // 机器可读的错误原因（如 USER_NOT_FOUND），前端可据此分支处理
+	Metadata  map[string]string `json:"metadata,omitempty" xml:"-"` // 错误元数据（XML 不支持 map，不输出）
+	RequestID string            `json:"requestId,omitempty"`        // 请求 ID
+	Details   []FieldError      `json:"details,omitempty"`          // 字段级错误详情
+}
+
+// FieldError 字段级错误
+type FieldError struct {
+	Field   string `json:"field"`   // 字段名
+	Message string `json:"message"` // 错误信息
 }
 
 // Success 创建成功响应
@@ -27,3 +48,78 @@ func Error(code int, message string) *Response {
 		Error:   message,
 	}
 }
+
+// FromError 根据错误创建错误响应
+// 保留 Kratos 错误的 reason 与 metadata，metadata 中 field. 前缀的条目转换为字段级错误详情
+func FromError(err error) *Response {
+	se := errors.FromError(err)
+	if se == nil {
+		se = errors.InternalServer(errors.UnknownReason, "unknown error")
+	}
+	resp := Error(int(se.Code), se.Message)
+	resp.Reason = se.Reason
+	resp.Metadata, resp.Details = splitMetadata(se.Metadata)
+	return resp
+}
+
+// WithFieldError 为错误添加字段级错误详情
+// 例如：response.WithFieldError(errors.BadRequest("INVALID_ARGUMENT", "参数错误"), "email", "邮箱格式不正确")
+func WithFieldError(err *errors.Error, field, message string) *errors.Error {
+	md := make(map[string]string, len(err.Metadata)+1)
+	for k, v := range err.Metadata {
+		md[k] = v
+	}
+	md[FieldErrorPrefix+field] = message
+	return err.WithMetadata(md)
+}
+
+// splitMetadata 将错误元数据拆分为普通元数据与字段级错误详情
+func splitMetadata(md map[string]string) (map[string]string, []FieldError) {
+	if len(md) == 0 {
+		return nil, nil
+	}
+	var (
+		metadata map[string]string
+		details  []FieldError
+	)
+	for k, v := range md {
+		if field, ok := strings.CutPrefix(k, FieldErrorPrefix); ok {
+			details = append(details, FieldError{Field: field, Message: v})
+			continue
+		}
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		metadata[k] = v
+	}
+	sort.Slice(details, func(i, j int) bool {
+		return details[i].Field < details[j].Field
+	})
+	return metadata, details
+}
+
+// Problem RFC 7807 错误响应结构（application/problem+json）
+type Problem struct {
+	Type     string `json:"type"`               // 问题类型 URI，默认 about:blank
+	Title    string `json:"title"`              // 问题简述（HTTP 状态码描述）
+	Status   int    `json:"status"`             // HTTP 状态码
+	Detail   string `json:"detail,omitempty"`   // 问题详情
+	Instance string `json:"instance,omitempty"` // 出错的请求路径
+
+	// 扩展字段，与统一响应结构保持一致
+	Reason    string            `json:"reason,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	RequestID string            `json:"requestId,omitempty"`
+	Details   []FieldError      `json:"details,omitempty"`
+}
+
+// NewProblem 创建 RFC 7807 错误响应
+func NewProblem(status int, detail, instance string) *Problem {
+	return &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+	}
+}
@@ -0,0 +1,146 @@
+package selector
+
+import (
+	"cmp"
+	"context"
+	"hash/crc32"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-kratos/kratos/v2/metadata"
+	"github.com/go-kratos/kratos/v2/selector"
+)
+
+// virtualNodes 一致性哈希环上每个实例的虚拟节点数
+const virtualNodes = 160
+
+var (
+	_ selector.Balancer = (*roundRobin)(nil)
+	_ selector.Balancer = (*consistentHash)(nil)
+)
+
+type roundRobinBuilder struct{}
+
+func (b *roundRobinBuilder) Build() selector.Balancer {
+	return &roundRobin{}
+}
+
+// roundRobin 轮询，不考虑实例权重
+type roundRobin struct {
+	next atomic.Uint64
+}
+
+// Pick 选择节点
+func (b *roundRobin) Pick(_ context.Context, nodes []selector.WeightedNode) (selector.WeightedNode, selector.DoneFunc, error) {
+	if len(nodes) == 0 {
+		return nil, nil, selector.ErrNoAvailable
+	}
+	selected := nodes[(b.next.Add(1)-1)%uint64(len(nodes))]
+	return selected, selected.Pick(), nil
+}
+
+type consistentHashBuilder struct {
+	key string
+}
+
+func (b *consistentHashBuilder) Build() selector.Balancer {
+	return &consistentHash{key: b.key}
+}
+
+// consistentHash 一致性哈希，相同哈希键的请求落到同一实例，实例增减时只影响少量请求
+type consistentHash struct {
+	key string
+
+	mu        sync.Mutex
+	signature string   // 当前哈希环对应的实例地址
+	hashes    []uint32 // 有序的虚拟节点哈希值
+	addrs     []string // 虚拟节点对应的实例地址
+}
+
+// Pick 选择节点，请求中没有哈希键时随机选择
+func (b *consistentHash) Pick(ctx context.Context, nodes []selector.WeightedNode) (selector.WeightedNode, selector.DoneFunc, error) {
+	if len(nodes) == 0 {
+		return nil, nil, selector.ErrNoAvailable
+	}
+	value := hashValue(ctx, b.key)
+	if value == "" {
+		selected := nodes[rand.IntN(len(nodes))]
+		return selected, selected.Pick(), nil
+	}
+
+	addr := b.lookup(nodes, crc32.ChecksumIEEE([]byte(value)))
+	for _, n := range nodes {
+		if n.Address() == addr {
+			return n, n.Pick(), nil
+		}
+	}
+	return nil, nil, selector.ErrNoAvailable
+}
+
+// lookup 在哈希环上查找哈希值对应的实例地址，实例变化时重建哈希环
+func (b *consistentHash) lookup(nodes []selector.WeightedNode, h uint32) string {
+	addrs := make([]string, len(nodes))
+	for i, n := range nodes {
+		addrs[i] = n.Address()
+	}
+	slices.Sort(addrs)
+	signature := strings.Join(addrs, ",")
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if signature != b.signature {
+		b.build(addrs)
+		b.signature = signature
+	}
+	i, _ := slices.BinarySearch(b.hashes, h)
+	if i == len(b.hashes) {
+		i = 0
+	}
+	return b.addrs[i]
+}
+
+// build 构建哈希环
+func (b *consistentHash) build(addrs []string) {
+	type point struct {
+		hash uint32
+		addr string
+	}
+	points := make([]point, 0, len(addrs)*virtualNodes)
+	for _, addr := range addrs {
+		for i := 0; i < virtualNodes; i++ {
+			points = append(points, point{hash: crc32.ChecksumIEEE([]byte(addr + "#" + strconv.Itoa(i))), addr: addr})
+		}
+	}
+	slices.SortFunc(points, func(a, b point) int {
+		if c := cmp.Compare(a.hash, b.hash); c != 0 {
+			return c
+		}
+		return strings.Compare(a.addr, b.addr)
+	})
+
+	b.hashes = make([]uint32, len(points))
+	b.addrs = make([]string, len(points))
+	for i, p := range points {
+		b.hashes[i], b.addrs[i] = p.hash, p.addr
+	}
+}
+
+// hashValue 从请求元数据中读取哈希键的值：优先取客户端元数据，其次取服务端收到的全局元数据（x-md-global-*）
+func hashValue(ctx context.Context, key string) string {
+	if key == "" {
+		return ""
+	}
+	if md, ok := metadata.FromClientContext(ctx); ok {
+		if v := md.Get(key); v != "" {
+			return v
+		}
+	}
+	if md, ok := metadata.FromServerContext(ctx); ok {
+		return md.Get(key)
+	}
+	return ""
+}
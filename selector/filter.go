@@ -0,0 +1,72 @@
+package selector
+
+import (
+	"context"
+
+	"github.com/addls/go-boot/config"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-kratos/kratos/v2/selector/filter"
+)
+
+// MetadataZone 实例所在可用区的元数据键，与 app.metadata.zone 对应
+const MetadataZone = "zone"
+
+// Filters 根据目标服务配置创建节点过滤器
+// 依次按版本、元数据过滤，开启同可用区优先时再优先选择与本实例相同可用区的实例
+func Filters(cfg *config.Config, target string) []selector.NodeFilter {
+	var filters []selector.NodeFilter
+	t := cfg.Client.Targets[target]
+	if t.Version != "" {
+		filters = append(filters, filter.Version(t.Version))
+	}
+	if len(t.Metadata) > 0 {
+		filters = append(filters, Metadata(t.Metadata))
+	}
+
+	zoneAffinity := cfg.Client.ZoneAffinity
+	if t.ZoneAffinity != nil {
+		zoneAffinity = *t.ZoneAffinity
+	}
+	if zone := cfg.App.Metadata[MetadataZone]; zoneAffinity && zone != "" {
+		filters = append(filters, Zone(zone))
+	}
+	return filters
+}
+
+// Metadata 只保留元数据全部匹配的节点
+func Metadata(md map[string]string) selector.NodeFilter {
+	return func(_ context.Context, nodes []selector.Node) []selector.Node {
+		filtered := make([]selector.Node, 0, len(nodes))
+		for _, n := range nodes {
+			if matchMetadata(n.Metadata(), md) {
+				filtered = append(filtered, n)
+			}
+		}
+		return filtered
+	}
+}
+
+// Zone 优先选择指定可用区的节点，该可用区没有节点时返回全部节点
+func Zone(zone string) selector.NodeFilter {
+	return func(_ context.Context, nodes []selector.Node) []selector.Node {
+		filtered := make([]selector.Node, 0, len(nodes))
+		for _, n := range nodes {
+			if n.Metadata()[MetadataZone] == zone {
+				filtered = append(filtered, n)
+			}
+		}
+		if len(filtered) == 0 {
+			return nodes
+		}
+		return filtered
+	}
+}
+
+func matchMetadata(got, want map[string]string) bool {
+	for k, v := range want {
+		if got[k] != v {
+			return false
+		}
+	}
+	return true
+}
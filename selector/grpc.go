@@ -0,0 +1,150 @@
+package selector
+
+import (
+	"encoding/json"
+
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/grpc"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/serviceconfig"
+)
+
+// BalancerName go-boot 的 gRPC 负载均衡器名称
+// 与 Kratos 的 selector 负载均衡器相同，但算法通过 service config 按连接传入，不使用全局 Selector
+const BalancerName = "go_boot_selector"
+
+var (
+	_ balancer.ConfigParser = (*grpcBalancerBuilder)(nil)
+	_ base.PickerBuilder    = (*grpcPickerBuilder)(nil)
+	_ balancer.Picker       = (*grpcPicker)(nil)
+)
+
+func init() {
+	balancer.Register(&grpcBalancerBuilder{})
+}
+
+// grpcConfig 负载均衡器配置，对应 service config 中的 loadBalancingConfig
+type grpcConfig struct {
+	serviceconfig.LoadBalancingConfig `json:"-"`
+
+	Balancer string `json:"balancer,omitempty"`
+	HashKey  string `json:"hashKey,omitempty"`
+}
+
+// DialOption 返回使用该 Builder 负载均衡算法的 gRPC 连接选项
+// 通过 grpc.WithOptions 传入，覆盖 Kratos 默认设置的 service config（保留健康检查配置）
+func (b *Builder) DialOption() ggrpc.DialOption {
+	sc, _ := json.Marshal(map[string]interface{}{
+		"loadBalancingConfig": []interface{}{
+			map[string]interface{}{BalancerName: grpcConfig{Balancer: b.balancer, HashKey: b.hashKey}},
+		},
+		"healthCheckConfig": map[string]string{"serviceName": ""},
+	})
+	return ggrpc.WithDefaultServiceConfig(string(sc))
+}
+
+// grpcBalancerBuilder 创建负载均衡器，并解析连接的负载均衡配置
+type grpcBalancerBuilder struct{}
+
+func (*grpcBalancerBuilder) Name() string {
+	return BalancerName
+}
+
+func (*grpcBalancerBuilder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	picker := &grpcPickerBuilder{builder: &Builder{}}
+	return &grpcBalancer{
+		Balancer: base.NewBalancerBuilder(BalancerName, picker, base.Config{HealthCheck: true}).Build(cc, opts),
+		picker:   picker,
+	}
+}
+
+func (*grpcBalancerBuilder) ParseConfig(js json.RawMessage) (serviceconfig.LoadBalancingConfig, error) {
+	cfg := &grpcConfig{}
+	if err := json.Unmarshal(js, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// grpcBalancer 在连接状态更新时设置负载均衡算法，其余逻辑委托给 gRPC 的 base 负载均衡器
+// gRPC 串行调用负载均衡器的方法，Picker 也在这些方法中同步创建，因此无需加锁
+type grpcBalancer struct {
+	balancer.Balancer
+	picker *grpcPickerBuilder
+}
+
+func (b *grpcBalancer) UpdateClientConnState(s balancer.ClientConnState) error {
+	if cfg, ok := s.BalancerConfig.(*grpcConfig); ok {
+		b.picker.builder = &Builder{balancer: cfg.Balancer, hashKey: cfg.HashKey}
+	}
+	return b.Balancer.UpdateClientConnState(s)
+}
+
+func (b *grpcBalancer) ExitIdle() {
+	if e, ok := b.Balancer.(balancer.ExitIdler); ok {
+		e.ExitIdle()
+	}
+}
+
+// grpcPickerBuilder 就绪连接变化时用当前算法创建 Picker
+type grpcPickerBuilder struct {
+	builder *Builder
+}
+
+func (b *grpcPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		// 阻塞请求，直到有可用连接
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	nodes := make([]selector.Node, 0, len(info.ReadySCs))
+	for conn, sc := range info.ReadySCs {
+		// Kratos 的服务发现 resolver 在地址属性中保存原始实例
+		ins, _ := sc.Address.Attributes.Value("rawServiceInstance").(*registry.ServiceInstance)
+		nodes = append(nodes, &grpcNode{
+			Node:    selector.NewNode("grpc", sc.Address.Addr, ins),
+			subConn: conn,
+		})
+	}
+	p := &grpcPicker{selector: b.builder.Build()}
+	p.selector.Apply(nodes)
+	return p
+}
+
+// grpcPicker 按 Selector 选择连接，支持通过 grpc.WithNodeFilter 设置的节点过滤器
+type grpcPicker struct {
+	selector selector.Selector
+}
+
+func (p *grpcPicker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	var filters []selector.NodeFilter
+	if tr, ok := transport.FromClientContext(info.Ctx); ok {
+		if gtr, ok := tr.(*grpc.Transport); ok {
+			filters = gtr.NodeFilters()
+		}
+	}
+
+	n, done, err := p.selector.Select(info.Ctx, selector.WithNodeFilter(filters...))
+	if err != nil {
+		return balancer.PickResult{}, err
+	}
+	return balancer.PickResult{
+		SubConn: n.(*grpcNode).subConn,
+		Done: func(di balancer.DoneInfo) {
+			done(info.Ctx, selector.DoneInfo{
+				Err:           di.Err,
+				BytesSent:     di.BytesSent,
+				BytesReceived: di.BytesReceived,
+				ReplyMD:       grpc.Trailer(di.Trailer),
+			})
+		},
+	}, nil
+}
+
+type grpcNode struct {
+	selector.Node
+	subConn balancer.SubConn
+}
@@ -0,0 +1,95 @@
+package selector
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/addls/go-boot/config"
+	"github.com/go-kratos/kratos/v2/metadata"
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector/filter"
+	"github.com/go-kratos/kratos/v2/transport/grpc"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+)
+
+// healthServer 启动只注册健康检查服务的 gRPC 服务，返回监听地址
+func healthServer(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := ggrpc.NewServer()
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
+
+func TestGRPCBalancer(t *testing.T) {
+	const hashKey = "x-md-global-uid"
+	a, b, c := healthServer(t), healthServer(t), healthServer(t)
+	d := newFakeDiscovery()
+	go d.push([]*registry.ServiceInstance{
+		{ID: "a", Name: "order", Version: "v1", Endpoints: []string{"http://127.0.0.1:8000", "grpc://" + a}},
+		{ID: "b", Name: "order", Version: "v1", Endpoints: []string{"grpc://" + b}},
+		{ID: "c", Name: "order", Version: "v2", Endpoints: []string{"grpc://" + c}},
+	})
+
+	conn, err := grpc.DialInsecure(context.Background(),
+		grpc.WithEndpoint("discovery:///order"),
+		grpc.WithDiscovery(d),
+		grpc.WithNodeFilter(filter.Version("v1")),
+		grpc.WithOptions(NewBuilder(&config.Client{Targets: map[string]config.ClientTarget{"order": {Balancer: ConsistentHash, HashKey: hashKey}}}, "order").DialOption()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+	pick := func(uid string) (string, error) {
+		ctx := metadata.NewClientContext(context.Background(), metadata.New(map[string][]string{hashKey: {uid}}))
+		var p peer.Peer
+		if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, ggrpc.WaitForReady(true), ggrpc.Peer(&p)); err != nil {
+			return "", err
+		}
+		return p.Addr.String(), nil
+	}
+
+	// 连接陆续就绪时 Picker 会重建（只有 c 就绪时过滤后没有可用节点），等待 a、b 都被选中；按版本过滤后不会选择 c
+	deadline := time.Now().Add(5 * time.Second)
+	owner := make(map[string]string)
+	for seen := make(map[string]bool); !seen[a] || !seen[b]; {
+		if time.Now().After(deadline) {
+			t.Fatalf("picked %v, want both %s and %s", seen, a, b)
+		}
+		uid := fmt.Sprintf("user-%d", len(owner))
+		addr, err := pick(uid)
+		if err != nil {
+			continue
+		}
+		if addr != a && addr != b {
+			t.Fatalf("picked %s, want %s or %s", addr, a, b)
+		}
+		seen[addr], owner[uid] = true, addr
+	}
+
+	// 使用目标服务配置的一致性哈希，而不是 Kratos 全局的加权轮询：相同哈希键总是落到同一实例
+	for uid := range owner {
+		want, err := pick(uid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			if addr, err := pick(uid); err != nil || addr != want {
+				t.Fatalf("%s picked %s, %v, want %s", uid, addr, err, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package selector
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector"
+)
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// Transport 按目标服务负载均衡算法选择实例的 HTTP RoundTripper
+// Kratos 的 HTTP 客户端只能使用全局 Selector，因此客户端以目标服务名作为地址且不设置 Discovery，
+// 由 Transport 监听服务发现、选择实例并改写请求地址
+type Transport struct {
+	target   string
+	base     http.RoundTripper
+	watcher  registry.Watcher
+	selector selector.Selector
+	filters  []selector.NodeFilter
+
+	ready chan struct{}
+	once  sync.Once
+}
+
+// NewTransport 监听目标服务实例并创建 Transport，使用 http.DefaultTransport 发送请求
+func NewTransport(ctx context.Context, discovery registry.Discovery, target string, builder selector.Builder, filters ...selector.NodeFilter) (*Transport, error) {
+	w, err := discovery.Watch(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	t := &Transport{
+		target:   target,
+		base:     http.DefaultTransport,
+		watcher:  w,
+		selector: builder.Build(),
+		filters:  filters,
+		ready:    make(chan struct{}),
+	}
+	go t.watch()
+	return t, nil
+}
+
+// watch 持续更新实例，直到 watcher 停止
+func (t *Transport) watch() {
+	for {
+		services, err := t.watcher.Next()
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			log.Errorf("failed to watch discovery endpoint %s: %v", t.target, err)
+			time.Sleep(time.Second)
+			continue
+		}
+		t.update(services)
+	}
+}
+
+// update 只保留 http 端点的实例，与 Kratos 一致，没有可用实例时保留上一次的实例
+func (t *Transport) update(services []*registry.ServiceInstance) {
+	nodes := make([]selector.Node, 0, len(services))
+	for _, ins := range services {
+		if addr := httpAddress(ins.Endpoints); addr != "" {
+			nodes = append(nodes, selector.NewNode("http", addr, ins))
+		}
+	}
+	if len(nodes) == 0 {
+		log.Warnf("zero http endpoint found for %s, keep the last instances", t.target)
+		return
+	}
+	t.selector.Apply(nodes)
+	t.once.Do(func() { close(t.ready) })
+}
+
+// RoundTrip 选择实例发送请求，首次收到实例前等待直到请求超时
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	select {
+	case <-t.ready:
+	case <-ctx.Done():
+		return nil, errors.ServiceUnavailable("NODE_NOT_FOUND", ctx.Err().Error())
+	}
+	node, done, err := t.selector.Select(ctx, selector.WithNodeFilter(t.filters...))
+	if err != nil {
+		return nil, errors.ServiceUnavailable("NODE_NOT_FOUND", err.Error())
+	}
+
+	req = req.Clone(ctx)
+	req.URL.Host = node.Address()
+	req.Host = node.Address()
+	resp, err := t.base.RoundTrip(req)
+	done(ctx, selector.DoneInfo{Err: err})
+	return resp, err
+}
+
+// Close 停止监听服务发现
+func (t *Transport) Close() error {
+	return t.watcher.Stop()
+}
+
+// httpAddress 返回实例的 http 端点地址，没有时返回空
+func httpAddress(endpoints []string) string {
+	for _, e := range endpoints {
+		u, err := url.Parse(e)
+		if err != nil {
+			continue
+		}
+		if u.Scheme == "http" {
+			return u.Host
+		}
+	}
+	return ""
+}
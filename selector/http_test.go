@@ -0,0 +1,128 @@
+package selector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/addls/go-boot/config"
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector/filter"
+)
+
+// fakeDiscovery 通过 push 推送实例变化的服务发现
+type fakeDiscovery struct {
+	updates chan []*registry.ServiceInstance
+}
+
+func newFakeDiscovery() *fakeDiscovery {
+	return &fakeDiscovery{updates: make(chan []*registry.ServiceInstance)}
+}
+
+// push 推送实例并等待处理完成：watcher 再次调用 Next 时说明上一次的实例已经处理
+func (d *fakeDiscovery) push(services []*registry.ServiceInstance) {
+	d.updates <- services
+	d.updates <- services
+}
+
+func (d *fakeDiscovery) GetService(context.Context, string) ([]*registry.ServiceInstance, error) {
+	return nil, nil
+}
+
+func (d *fakeDiscovery) Watch(context.Context, string) (registry.Watcher, error) {
+	return &fakeWatcher{updates: d.updates, stop: make(chan struct{})}, nil
+}
+
+type fakeWatcher struct {
+	updates chan []*registry.ServiceInstance
+	stop    chan struct{}
+	once    sync.Once
+}
+
+func (w *fakeWatcher) Next() ([]*registry.ServiceInstance, error) {
+	select {
+	case services := <-w.updates:
+		return services, nil
+	case <-w.stop:
+		return nil, context.Canceled
+	}
+}
+
+func (w *fakeWatcher) Stop() error {
+	w.once.Do(func() { close(w.stop) })
+	return nil
+}
+
+// namedServer 返回响应体为 name 的 HTTP 服务
+func namedServer(t *testing.T, name string) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, name)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestTransport(t *testing.T) {
+	a, b, c := namedServer(t, "a"), namedServer(t, "b"), namedServer(t, "c")
+	d := newFakeDiscovery()
+	tr, err := NewTransport(context.Background(), d, "order",
+		NewBuilder(&config.Client{Balancer: RoundRobin}, "order"), filter.Version("v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Close()
+	client := &http.Client{Transport: tr}
+
+	get := func(timeout time.Duration) (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://order/ping", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	// 首次收到实例前请求等待直到超时
+	if _, err := get(50 * time.Millisecond); err == nil {
+		t.Fatal("request before discovery should fail")
+	}
+
+	d.push([]*registry.ServiceInstance{
+		{ID: "a", Name: "order", Version: "v1", Endpoints: []string{"grpc://127.0.0.1:9000", a.URL}},
+		{ID: "b", Name: "order", Version: "v1", Endpoints: []string{b.URL}},
+		{ID: "c", Name: "order", Version: "v2", Endpoints: []string{c.URL}},
+		{ID: "d", Name: "order", Version: "v1", Endpoints: []string{"grpc://127.0.0.1:9001"}},
+	})
+	seen := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		name, err := get(time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen[name]++
+	}
+	if seen["a"] != 2 || seen["b"] != 2 {
+		t.Fatalf("round robin with version filter picked %v, want a and b twice", seen)
+	}
+
+	// 没有 http 端点时保留上一次的实例
+	d.push([]*registry.ServiceInstance{{ID: "d", Name: "order", Version: "v1", Endpoints: []string{"grpc://127.0.0.1:9001"}}})
+	if _, err := get(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	d.push([]*registry.ServiceInstance{{ID: "b", Name: "order", Version: "v1", Endpoints: []string{b.URL}}})
+	for i := 0; i < 2; i++ {
+		if name, err := get(time.Second); err != nil || name != "b" {
+			t.Fatalf("after update picked %q, %v, want b", name, err)
+		}
+	}
+}
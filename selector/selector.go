@@ -0,0 +1,66 @@
+package selector
+
+import (
+	"github.com/addls/go-boot/config"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-kratos/kratos/v2/selector/node/direct"
+	"github.com/go-kratos/kratos/v2/selector/p2c"
+	"github.com/go-kratos/kratos/v2/selector/random"
+	"github.com/go-kratos/kratos/v2/selector/wrr"
+)
+
+// 负载均衡算法
+const (
+	RoundRobin         = "rr"     // 轮询
+	WeightedRoundRobin = "wrr"    // 按实例 metadata.weight 加权轮询（默认）
+	Random             = "random" // 随机
+	P2C                = "p2c"    // 两次随机选择，按 EWMA 延迟与在途请求数选优
+	ConsistentHash     = "hash"   // 按请求元数据一致性哈希
+)
+
+var _ selector.Builder = (*Builder)(nil)
+
+// Builder 目标服务的 Selector Builder
+// 负载均衡算法在创建时按目标服务配置确定，每个客户端连接单独使用，不依赖 Kratos 的全局 Selector
+type Builder struct {
+	balancer string
+	hashKey  string
+}
+
+// NewBuilder 根据客户端配置创建目标服务的 Selector Builder，目标服务未配置时使用默认配置
+func NewBuilder(cfg *config.Client, target string) *Builder {
+	b := &Builder{balancer: cfg.Balancer, hashKey: cfg.HashKey}
+	if t, ok := cfg.Targets[target]; ok {
+		if t.Balancer != "" {
+			b.balancer = t.Balancer
+		}
+		if t.HashKey != "" {
+			b.hashKey = t.HashKey
+		}
+	}
+	return b
+}
+
+// Build 创建 Selector
+func (b *Builder) Build() selector.Selector {
+	return newSelector(b.balancer, b.hashKey)
+}
+
+// newSelector 根据算法名称创建 Kratos Selector
+func newSelector(balancer, hashKey string) selector.Selector {
+	switch balancer {
+	case "", WeightedRoundRobin:
+		return wrr.NewBuilder().Build()
+	case RoundRobin:
+		return (&selector.DefaultBuilder{Node: &direct.Builder{}, Balancer: &roundRobinBuilder{}}).Build()
+	case Random:
+		return random.NewBuilder().Build()
+	case P2C:
+		return p2c.NewBuilder().Build()
+	case ConsistentHash:
+		return (&selector.DefaultBuilder{Node: &direct.Builder{}, Balancer: &consistentHashBuilder{key: hashKey}}).Build()
+	default:
+		// 配置中的算法名称已由 config.Validate 校验
+		return wrr.NewBuilder().Build()
+	}
+}
@@ -0,0 +1,197 @@
+package selector
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/addls/go-boot/config"
+	"github.com/go-kratos/kratos/v2/metadata"
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-kratos/kratos/v2/selector/node/direct"
+)
+
+func newNode(addr, version string, md map[string]string) selector.Node {
+	return selector.NewNode("grpc", addr, &registry.ServiceInstance{Name: "order", Version: version, Metadata: md})
+}
+
+func addrs(nodes []selector.Node) []string {
+	out := make([]string, len(nodes))
+	for i, n := range nodes {
+		out[i] = n.Address()
+	}
+	return out
+}
+
+func TestFilters(t *testing.T) {
+	nodes := []selector.Node{
+		newNode("a", "v1", map[string]string{"zone": "z1", "env": "prod"}),
+		newNode("b", "v1", map[string]string{"zone": "z2", "env": "prod"}),
+		newNode("c", "v2", map[string]string{"zone": "z1", "env": "canary"}),
+		newNode("d", "v2", map[string]string{"zone": "z2", "env": "canary"}),
+	}
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name   string
+		client config.Client
+		zone   string
+		want   []string
+	}{
+		{name: "no filter", want: []string{"a", "b", "c", "d"}},
+		{name: "version", client: config.Client{Targets: map[string]config.ClientTarget{"order": {Version: "v2"}}}, want: []string{"c", "d"}},
+		{name: "metadata", client: config.Client{Targets: map[string]config.ClientTarget{"order": {Metadata: map[string]string{"env": "prod"}}}}, want: []string{"a", "b"}},
+		{name: "zone affinity", client: config.Client{ZoneAffinity: true}, zone: "z2", want: []string{"b", "d"}},
+		{name: "zone affinity without local zone", client: config.Client{ZoneAffinity: true}, want: []string{"a", "b", "c", "d"}},
+		{name: "zone without nodes falls back", client: config.Client{ZoneAffinity: true}, zone: "z3", want: []string{"a", "b", "c", "d"}},
+		{name: "target disables zone affinity", client: config.Client{ZoneAffinity: true, Targets: map[string]config.ClientTarget{"order": {ZoneAffinity: &disabled}}}, zone: "z1", want: []string{"a", "b", "c", "d"}},
+		{name: "target enables zone affinity", client: config.Client{Targets: map[string]config.ClientTarget{"order": {ZoneAffinity: &enabled}}}, zone: "z1", want: []string{"a", "c"}},
+		{
+			name:   "version, metadata and zone",
+			client: config.Client{ZoneAffinity: true, Targets: map[string]config.ClientTarget{"order": {Version: "v2", Metadata: map[string]string{"env": "canary"}}}},
+			zone:   "z1",
+			want:   []string{"c"},
+		},
+		{name: "other target", client: config.Client{Targets: map[string]config.ClientTarget{"user": {Version: "v2"}}}, want: []string{"a", "b", "c", "d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Client: tt.client}
+			if tt.zone != "" {
+				cfg.App.Metadata = map[string]string{MetadataZone: tt.zone}
+			}
+			got := nodes
+			for _, f := range Filters(cfg, "order") {
+				got = f(context.Background(), got)
+			}
+			if !reflect.DeepEqual(addrs(got), tt.want) {
+				t.Fatalf("filtered = %v, want %v", addrs(got), tt.want)
+			}
+		})
+	}
+}
+
+func weightedNodes(addrs ...string) []selector.WeightedNode {
+	b := &direct.Builder{}
+	nodes := make([]selector.WeightedNode, len(addrs))
+	for i, addr := range addrs {
+		nodes[i] = b.Build(newNode(addr, "", nil))
+	}
+	return nodes
+}
+
+func TestRoundRobin(t *testing.T) {
+	b := (&roundRobinBuilder{}).Build()
+	nodes := weightedNodes("a", "b", "c")
+	var got []string
+	for i := 0; i < 6; i++ {
+		n, _, err := b.Pick(context.Background(), nodes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, n.Address())
+	}
+	if want := []string{"a", "b", "c", "a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("picked %v, want %v", got, want)
+	}
+	if _, _, err := b.Pick(context.Background(), nil); err != selector.ErrNoAvailable {
+		t.Fatalf("Pick() with no nodes error = %v, want ErrNoAvailable", err)
+	}
+}
+
+func TestConsistentHash(t *testing.T) {
+	const key = "x-md-global-uid"
+	pick := func(b selector.Balancer, nodes []selector.WeightedNode, uid string) string {
+		t.Helper()
+		ctx := context.Background()
+		if uid != "" {
+			ctx = metadata.NewClientContext(ctx, metadata.New(map[string][]string{key: {uid}}))
+		}
+		n, _, err := b.Pick(ctx, nodes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n.Address()
+	}
+
+	b := (&consistentHashBuilder{key: key}).Build()
+	nodes := weightedNodes("10.0.0.1:9000", "10.0.0.2:9000", "10.0.0.3:9000", "10.0.0.4:9000")
+	before := make(map[string]string)
+	for i := 0; i < 200; i++ {
+		uid := fmt.Sprintf("user-%d", i)
+		before[uid] = pick(b, nodes, uid)
+		// 相同哈希键总是落到同一实例，与节点顺序无关
+		reversed := []selector.WeightedNode{nodes[3], nodes[2], nodes[1], nodes[0]}
+		if got := pick(b, reversed, uid); got != before[uid] {
+			t.Fatalf("%s picked %s after reordering, want %s", uid, got, before[uid])
+		}
+	}
+
+	// 移除一个实例后，只有原来落在该实例上的请求会迁移
+	removed := "10.0.0.4:9000"
+	for uid, addr := range before {
+		got := pick(b, nodes[:3], uid)
+		if addr != removed && got != addr {
+			t.Fatalf("%s moved from %s to %s after removing %s", uid, addr, got, removed)
+		}
+		if got == removed {
+			t.Fatalf("%s picked removed node", uid)
+		}
+	}
+
+	// 没有哈希键时随机选择
+	if addr := pick(b, nodes, ""); addr == "" {
+		t.Fatal("Pick() without hash key should pick a node")
+	}
+}
+
+func TestNewBuilder(t *testing.T) {
+	cfg := &config.Client{
+		Balancer: RoundRobin,
+		HashKey:  "x-md-global-uid",
+		Targets: map[string]config.ClientTarget{
+			"order": {Balancer: ConsistentHash},
+			"user":  {HashKey: "x-md-global-tenant"},
+		},
+	}
+	tests := []struct {
+		target string
+		want   Builder
+	}{
+		{"order", Builder{balancer: ConsistentHash, hashKey: "x-md-global-uid"}},
+		{"user", Builder{balancer: RoundRobin, hashKey: "x-md-global-tenant"}},
+		{"payment", Builder{balancer: RoundRobin, hashKey: "x-md-global-uid"}},
+	}
+	for _, tt := range tests {
+		if got := NewBuilder(cfg, tt.target); *got != tt.want {
+			t.Errorf("NewBuilder(%q) = %+v, want %+v", tt.target, *got, tt.want)
+		}
+	}
+}
+
+func TestBuilderBuild(t *testing.T) {
+	// 算法在创建时确定，与节点的服务名无关
+	sel := NewBuilder(&config.Client{Targets: map[string]config.ClientTarget{"order": {Balancer: RoundRobin}}}, "order").Build()
+	if _, _, err := sel.Select(context.Background()); err != selector.ErrNoAvailable {
+		t.Fatalf("Select() before Apply error = %v, want ErrNoAvailable", err)
+	}
+	sel.Apply([]selector.Node{
+		selector.NewNode("grpc", "a", &registry.ServiceInstance{Name: "order-v2"}),
+		selector.NewNode("grpc", "b", &registry.ServiceInstance{Name: "order-v2"}),
+	})
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		n, done, err := sel.Select(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		done(context.Background(), selector.DoneInfo{})
+		got = append(got, n.Address())
+	}
+	if got[0] == got[1] || got[0] != got[2] || got[1] != got[3] {
+		t.Fatalf("round robin picked %v", got)
+	}
+}